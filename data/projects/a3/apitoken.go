@@ -0,0 +1,196 @@
+package hashpassword
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"strings"
+
+	"github.com/example/hashpassword/internal/cryptorand"
+)
+
+// base62Chars is the alphabet of token bodies and checksums; it avoids
+// characters that need quoting in shells, URLs or config files
+const base62Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const (
+	// DefaultTokenBodyLength is the default number of random base62
+	// characters in a token (about 190 bits)
+	DefaultTokenBodyLength = 32
+	// MinTokenBodyLength is the shortest body accepted (about 95 bits)
+	MinTokenBodyLength = 16
+	// tokenChecksumLength is the number of base62 characters encoding the CRC32
+	tokenChecksumLength = 6
+	// tokenSeparator separates prefix, body and checksum
+	tokenSeparator = "_"
+	// maxTokenPrefixLength bounds the prefix length
+	maxTokenPrefixLength = 16
+)
+
+var (
+	// ErrInvalidToken is returned when a token is not in prefix_body_checksum form
+	ErrInvalidToken = errors.New("invalid token format")
+	// ErrTokenChecksum is returned when a token's checksum does not match, e.g. after a typo
+	ErrTokenChecksum = errors.New("token checksum mismatch")
+	// ErrTokenPrefix is returned when a token has an unexpected prefix
+	ErrTokenPrefix = errors.New("unexpected token prefix")
+)
+
+// tokenPrefixPattern restricts prefixes to short lowercase identifiers
+var tokenPrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// tokenScanPattern matches candidate tokens in free text for FindTokens
+var tokenScanPattern = regexp.MustCompile(`\b[a-z][a-z0-9]{0,15}_[0-9A-Za-z]{16,}_[0-9A-Za-z]{6}\b`)
+
+// Token is a parsed API token of the form prefix_body_checksum,
+// e.g. "acme_3FZq...Xk9_1bC8aZ"
+type Token struct {
+	Prefix   string
+	Body     string
+	Checksum string
+}
+
+// String returns the token in its serialized form
+func (t *Token) String() string {
+	return t.Prefix + tokenSeparator + t.Body + tokenSeparator + t.Checksum
+}
+
+// GenerateToken generates a token with DefaultTokenBodyLength random characters
+func GenerateToken(prefix string) (string, error) {
+	return GenerateTokenWithLength(prefix, DefaultTokenBodyLength)
+}
+
+// GenerateTokenWithLength generates a token with a body of bodyLength random
+// base62 characters followed by a CRC32 checksum of the prefix and body
+func GenerateTokenWithLength(prefix string, bodyLength int) (string, error) {
+	if err := validateTokenPrefix(prefix); err != nil {
+		return "", err
+	}
+	if bodyLength < MinTokenBodyLength {
+		return "", fmt.Errorf("token body length must be at least %d", MinTokenBodyLength)
+	}
+
+	body := make([]byte, bodyLength)
+	for i := range body {
+		idx, err := cryptorand.Index(len(base62Chars))
+		if err != nil {
+			return "", err
+		}
+		body[i] = base62Chars[idx]
+	}
+
+	token := &Token{Prefix: prefix, Body: string(body)}
+	token.Checksum = tokenChecksum(token.Prefix, token.Body)
+	return token.String(), nil
+}
+
+// validateTokenPrefix checks that prefix is a short lowercase identifier
+func validateTokenPrefix(prefix string) error {
+	if len(prefix) == 0 || len(prefix) > maxTokenPrefixLength || !tokenPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("token prefix must be 1-%d lowercase letters or digits starting with a letter", maxTokenPrefixLength)
+	}
+	return nil
+}
+
+// tokenChecksum encodes the CRC32 of "prefix_body" as fixed width base62
+func tokenChecksum(prefix, body string) string {
+	sum := crc32.ChecksumIEEE([]byte(prefix + tokenSeparator + body))
+
+	checksum := make([]byte, tokenChecksumLength)
+	for i := tokenChecksumLength - 1; i >= 0; i-- {
+		checksum[i] = base62Chars[sum%62]
+		sum /= 62
+	}
+	return string(checksum)
+}
+
+// ParseToken splits a token into its parts and verifies its checksum offline.
+// It returns ErrInvalidToken for malformed input and ErrTokenChecksum when
+// the checksum does not match.
+func ParseToken(token string) (*Token, error) {
+	parts := strings.Split(token, tokenSeparator)
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	t := &Token{Prefix: parts[0], Body: parts[1], Checksum: parts[2]}
+	if validateTokenPrefix(t.Prefix) != nil {
+		return nil, ErrInvalidToken
+	}
+	if len(t.Body) < MinTokenBodyLength || len(t.Checksum) != tokenChecksumLength {
+		return nil, ErrInvalidToken
+	}
+	for _, part := range []string{t.Body, t.Checksum} {
+		for i := 0; i < len(part); i++ {
+			if strings.IndexByte(base62Chars, part[i]) < 0 {
+				return nil, ErrInvalidToken
+			}
+		}
+	}
+
+	if !hmac.Equal([]byte(tokenChecksum(t.Prefix, t.Body)), []byte(t.Checksum)) {
+		return nil, ErrTokenChecksum
+	}
+	return t, nil
+}
+
+// ParseTokenWithPrefix parses a token and additionally requires the given prefix
+func ParseTokenWithPrefix(token, prefix string) (*Token, error) {
+	t, err := ParseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if t.Prefix != prefix {
+		return nil, fmt.Errorf("%w: got %q, want %q", ErrTokenPrefix, t.Prefix, prefix)
+	}
+	return t, nil
+}
+
+// FindTokens returns every well-formed token with a valid checksum found in
+// text, for scanning logs, commits or config files for leaked secrets
+func FindTokens(text string) []string {
+	var found []string
+	for _, candidate := range tokenScanPattern.FindAllString(text, -1) {
+		if _, err := ParseToken(candidate); err == nil {
+			found = append(found, candidate)
+		}
+	}
+	return found
+}
+
+// HashToken validates a token and hashes it with HashPassword for storage
+func HashToken(token, secretKey string) (string, error) {
+	if _, err := ParseToken(token); err != nil {
+		return "", err
+	}
+	return HashPassword(token, secretKey)
+}
+
+// VerifyToken checks a presented token against a hash from HashToken.
+// Tokens with a bad checksum are rejected before any hashing is done.
+func VerifyToken(token, hash, secretKey string) (bool, error) {
+	if _, err := ParseToken(token); err != nil {
+		return false, err
+	}
+	return VerifyPassword(token, hash, secretKey)
+}
+
+// TokenLookupKey returns a deterministic hex HMAC-SHA256 of the token, usable
+// as an indexed database key to find a token's record without storing the
+// token itself. Use HashToken/VerifyToken for the salted comparison.
+func TokenLookupKey(token, secretKey string) (string, error) {
+	if secretKey == "" {
+		return "", ErrEmptySecretKey
+	}
+	if _, err := ParseToken(token); err != nil {
+		return "", err
+	}
+
+	h := hmac.New(sha256.New, []byte(secretKey))
+	h.Write([]byte(token))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,162 @@
+package hashpassword
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken("acme")
+	if err != nil {
+		t.Fatalf("GenerateToken() unexpected error = %v", err)
+	}
+
+	parts := strings.Split(token, "_")
+	if len(parts) != 3 || parts[0] != "acme" {
+		t.Fatalf("GenerateToken() = %q, want acme_body_checksum", token)
+	}
+	if len(parts[1]) != DefaultTokenBodyLength {
+		t.Errorf("GenerateToken() body length = %d, want %d", len(parts[1]), DefaultTokenBodyLength)
+	}
+	if strings.ContainsAny(token, "$|!@#%^&*(){}[];:,.<>?'\"` ") {
+		t.Errorf("GenerateToken() = %q contains shell or URL sensitive characters", token)
+	}
+
+	if _, err := ParseToken(token); err != nil {
+		t.Errorf("ParseToken(GenerateToken()) unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		prefix     string
+		bodyLength int
+	}{
+		{name: "Empty prefix", prefix: "", bodyLength: 32},
+		{name: "Uppercase prefix", prefix: "ACME", bodyLength: 32},
+		{name: "Prefix with separator", prefix: "ac_me", bodyLength: 32},
+		{name: "Prefix starting with digit", prefix: "1acme", bodyLength: 32},
+		{name: "Prefix too long", prefix: "abcdefghijklmnopq", bodyLength: 32},
+		{name: "Body too short", prefix: "acme", bodyLength: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateTokenWithLength(tt.prefix, tt.bodyLength); err == nil {
+				t.Error("GenerateTokenWithLength() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	token, _ := GenerateTokenWithLength("sk", 40)
+	parsed, err := ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken() unexpected error = %v", err)
+	}
+	if parsed.Prefix != "sk" || len(parsed.Body) != 40 || parsed.String() != token {
+		t.Errorf("ParseToken() = %+v, does not round trip %q", parsed, token)
+	}
+
+	// Flip one character of the body to simulate a typo
+	typo := []byte(token)
+	i := len("sk_") + 5
+	if typo[i] == 'a' {
+		typo[i] = 'b'
+	} else {
+		typo[i] = 'a'
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "Typo in body", token: string(typo), wantErr: ErrTokenChecksum},
+		{name: "Missing checksum", token: "sk_" + parsed.Body, wantErr: ErrInvalidToken},
+		{name: "Extra separator", token: token + "_x", wantErr: ErrInvalidToken},
+		{name: "Non base62 body", token: "sk_" + strings.Repeat("$", 20) + "_" + parsed.Checksum, wantErr: ErrInvalidToken},
+		{name: "Short checksum", token: "sk_" + parsed.Body + "_abc", wantErr: ErrInvalidToken},
+		{name: "Empty", token: "", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseToken(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseToken(%q) error = %v, want %v", tt.token, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParseTokenWithPrefix(token, "sk"); err != nil {
+		t.Errorf("ParseTokenWithPrefix() unexpected error = %v", err)
+	}
+	if _, err := ParseTokenWithPrefix(token, "pk"); !errors.Is(err, ErrTokenPrefix) {
+		t.Errorf("ParseTokenWithPrefix() error = %v, want ErrTokenPrefix", err)
+	}
+}
+
+func TestFindTokens(t *testing.T) {
+	token1, _ := GenerateToken("acme")
+	token2, _ := GenerateToken("ci")
+	forged := "acme_" + strings.Repeat("A", 32) + "_AAAAAA"
+
+	text := "export ACME_KEY=" + token1 + "\nlog: used " + token2 + " and " + forged + " at line 3"
+	found := FindTokens(text)
+
+	if len(found) != 2 || found[0] != token1 || found[1] != token2 {
+		t.Errorf("FindTokens() = %v, want [%s %s]", found, token1, token2)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	token, _ := GenerateToken("acme")
+
+	hash, err := HashToken(token, secretKey)
+	if err != nil {
+		t.Fatalf("HashToken() unexpected error = %v", err)
+	}
+	if strings.Contains(hash, token) {
+		t.Error("HashToken() result contains the token")
+	}
+
+	ok, err := VerifyToken(token, hash, secretKey)
+	if err != nil || !ok {
+		t.Errorf("VerifyToken() = %v, %v, want true", ok, err)
+	}
+
+	other, _ := GenerateToken("acme")
+	ok, err = VerifyToken(other, hash, secretKey)
+	if err != nil || ok {
+		t.Errorf("VerifyToken() other token = %v, %v, want false", ok, err)
+	}
+
+	if _, err := VerifyToken("acme_bad", hash, secretKey); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerifyToken() error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := HashToken("acme_bad", secretKey); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HashToken() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestTokenLookupKey(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	token, _ := GenerateToken("acme")
+
+	key1, err := TokenLookupKey(token, secretKey)
+	if err != nil {
+		t.Fatalf("TokenLookupKey() unexpected error = %v", err)
+	}
+	key2, _ := TokenLookupKey(token, secretKey)
+	if key1 != key2 || len(key1) != 64 {
+		t.Errorf("TokenLookupKey() = %q, %q, want identical 64 hex characters", key1, key2)
+	}
+
+	key3, _ := TokenLookupKey(token, "another-secret-key")
+	if key3 == key1 {
+		t.Error("TokenLookupKey() does not depend on the secret key")
+	}
+	if _, err := TokenLookupKey(token, ""); !errors.Is(err, ErrEmptySecretKey) {
+		t.Errorf("TokenLookupKey() error = %v, want ErrEmptySecretKey", err)
+	}
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	hashpassword "github.com/example/hashpassword"
+	"github.com/example/hashpassword/migrate"
+	passwordgen "github.com/example/hashpassword/password"
+)
+
+// hashResult is the output of hash and rehash
+type hashResult struct {
+	Hash string `json:"hash"`
+}
+
+// verifyResult is the output of verify
+type verifyResult struct {
+	Match       bool `json:"match"`
+	NeedsRehash bool `json:"needs_rehash"`
+}
+
+// inspectResult describes one hash for inspect
+type inspectResult struct {
+	Hash       string `json:"hash"`
+	Valid      bool   `json:"valid"`
+	Algorithm  string `json:"algorithm,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	SaltBytes  int    `json:"salt_bytes,omitempty"`
+	// HMACSaltBytes is the length of the original salt of a wrapped hash
+	HMACSaltBytes int    `json:"hmac_salt_bytes,omitempty"`
+	DigestBytes   int    `json:"digest_bytes,omitempty"`
+	NeedsRehash   bool   `json:"needs_rehash"`
+	Error         string `json:"error,omitempty"`
+}
+
+// generateResult is one password from generate
+type generateResult struct {
+	Password    string  `json:"password"`
+	EntropyBits float64 `json:"entropy_bits"`
+	CharsetSize int     `json:"charset_size"`
+	Hash        string  `json:"hash,omitempty"`
+}
+
+// migrateResult is the report of migrate
+type migrateResult struct {
+	Rows          int                    `json:"rows"`
+	Classes       []classResult          `json:"classes"`
+	Wrapped       int                    `json:"wrapped"`
+	Malformed     int                    `json:"malformed"`
+	MalformedRows []migrate.MalformedRow `json:"malformed_rows,omitempty"`
+}
+
+// classResult is the count of one class of hashes for migrate
+type classResult struct {
+	Algorithm string `json:"algorithm"`
+	Params    string `json:"params,omitempty"`
+	Encoding  string `json:"encoding"`
+	Count     int    `json:"count"`
+}
+
+// storedHash is a parsed hash, plain (*hashpassword.PasswordHash) or
+// wrapped in PBKDF2 (*hashpassword.WrappedHash)
+type storedHash interface {
+	NeedsRehash() bool
+}
+
+// parseHash parses a plain or wrapped hash argument, reporting a malformed
+// one with exitMalformed
+func parseHash(hash string) (storedHash, error) {
+	parsed, err := parseStoredHash(hash)
+	if err != nil {
+		return nil, withStatus(exitMalformed, err)
+	}
+	return parsed, nil
+}
+
+// parseStoredHash parses a hash with ParseWrappedHash or ParseHash,
+// depending on its algorithm
+func parseStoredHash(hash string) (storedHash, error) {
+	if algorithm, _, _ := strings.Cut(hash, hashpassword.Separator); algorithm == hashpassword.WrappedAlgorithmIdentifier {
+		wrapped, err := hashpassword.ParseWrappedHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		return wrapped, nil
+	}
+	parsed, err := hashpassword.ParseHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// checkSaltLength rejects salt lengths HashPasswordWithSaltLength would
+// refuse, before a password is asked for
+func checkSaltLength(n int) error {
+	if n < 16 {
+		return withStatus(exitUsage, fmt.Errorf("--salt-length must be at least 16, got %d", n))
+	}
+	return nil
+}
+
+func (a *app) hash(args []string) error {
+	fs := a.flags("hash", "")
+	secret := secretFlags(fs, "", defaultSecretEnv)
+	saltLength := fs.Int("salt-length", hashpassword.DefaultSaltLength, "salt length in `bytes`, at least 16")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := checkSaltLength(*saltLength); err != nil {
+		return err
+	}
+	key, err := a.secret(secret)
+	if err != nil {
+		return err
+	}
+
+	password, err := a.password("Password: ", true)
+	if err != nil {
+		return err
+	}
+	hash, err := hashpassword.HashPasswordWithSaltLength(password, key, *saltLength)
+	if err != nil {
+		return err
+	}
+	return a.print(hashResult{Hash: hash}, hash)
+}
+
+func (a *app) verify(args []string) error {
+	fs := a.flags("verify", "HASH")
+	secret := secretFlags(fs, "", defaultSecretEnv)
+	positional, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseHash(positional[0])
+	if err != nil {
+		return err
+	}
+	key, err := a.secret(secret)
+	if err != nil {
+		return err
+	}
+
+	password, err := a.password("Password: ", false)
+	if err != nil {
+		return err
+	}
+	match, err := hashpassword.VerifyPassword(password, positional[0], key)
+	if err != nil {
+		return err
+	}
+
+	text := "match"
+	if !match {
+		text = "mismatch"
+	}
+	if err := a.print(verifyResult{Match: match, NeedsRehash: parsed.NeedsRehash()}, text); err != nil {
+		return err
+	}
+	if !match {
+		return withStatus(exitMismatch, errSilent)
+	}
+	return nil
+}
+
+func (a *app) inspect(args []string) error {
+	fs := a.flags("inspect", "[HASH...]")
+	hashes, err := parse(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	// Without arguments, hashes are read from standard input, one per line
+	next := func() (string, bool) {
+		if len(hashes) == 0 {
+			return "", false
+		}
+		h := hashes[0]
+		hashes = hashes[1:]
+		return h, true
+	}
+	if len(hashes) == 0 {
+		next = func() (string, bool) {
+			for {
+				line, err := a.readLine()
+				if err != nil {
+					return "", false
+				}
+				if line = strings.TrimSpace(line); line != "" {
+					return line, true
+				}
+			}
+		}
+	}
+
+	total, malformed := 0, 0
+	for hash, ok := next(); ok; hash, ok = next() {
+		total++
+		result := inspectResult{Hash: hash}
+		var text string
+		parsed, err := parseStoredHash(hash)
+		switch parsed := parsed.(type) {
+		case *hashpassword.PasswordHash:
+			result.Valid = true
+			result.Algorithm = parsed.Algorithm
+			result.SaltBytes = len(parsed.Salt)
+			result.DigestBytes = len(parsed.Digest)
+			result.NeedsRehash = parsed.NeedsRehash()
+			text = fmt.Sprintf("algorithm=%s salt_bytes=%d digest_bytes=%d needs_rehash=%t",
+				result.Algorithm, result.SaltBytes, result.DigestBytes, result.NeedsRehash)
+		case *hashpassword.WrappedHash:
+			result.Valid = true
+			result.Algorithm = hashpassword.WrappedAlgorithmIdentifier
+			result.Iterations = parsed.Iterations
+			result.SaltBytes = len(parsed.Salt)
+			result.HMACSaltBytes = len(parsed.HMACSalt)
+			result.DigestBytes = len(parsed.Digest)
+			result.NeedsRehash = parsed.NeedsRehash()
+			text = fmt.Sprintf("algorithm=%s iterations=%d salt_bytes=%d hmac_salt_bytes=%d digest_bytes=%d needs_rehash=%t",
+				result.Algorithm, result.Iterations, result.SaltBytes, result.HMACSaltBytes, result.DigestBytes, result.NeedsRehash)
+		default:
+			malformed++
+			result.Error = err.Error()
+			text = "invalid: " + err.Error()
+		}
+		if err := a.print(result, text); err != nil {
+			return err
+		}
+	}
+
+	if malformed > 0 {
+		return withStatus(exitMalformed, fmt.Errorf("%d of %d hashes are malformed", malformed, total))
+	}
+	return nil
+}
+
+func (a *app) rehash(args []string) error {
+	fs := a.flags("rehash", "HASH")
+	secret := secretFlags(fs, "", defaultSecretEnv)
+	newSecret := secretFlags(fs, "new-", "")
+	saltLength := fs.Int("salt-length", hashpassword.DefaultSaltLength, "salt length of the new hash in `bytes`, at least 16")
+	fs.Usage = func() {
+		fmt.Fprint(a.stderr, "usage: hashpw rehash [flags] HASH\n\n"+
+			"Verifies the password against HASH and prints a new hash of it with a\n"+
+			"fresh salt, under the new secret key if one is given. A hash wrapped in\n"+
+			"PBKDF2 is replaced by a wrapped hash with at least the default iterations.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	positional, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseHash(positional[0])
+	if err != nil {
+		return err
+	}
+	if err := checkSaltLength(*saltLength); err != nil {
+		return err
+	}
+	key, err := a.secret(secret)
+	if err != nil {
+		return err
+	}
+	newKey := key
+	if newSecret.env != "" || newSecret.file != "" {
+		if newKey, err = a.secret(newSecret); err != nil {
+			return err
+		}
+	}
+
+	password, err := a.password("Password: ", false)
+	if err != nil {
+		return err
+	}
+	match, err := hashpassword.VerifyPassword(password, positional[0], key)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return withStatus(exitMismatch, errors.New("password does not match the hash"))
+	}
+
+	hash, err := hashpassword.HashPasswordWithSaltLength(password, newKey, *saltLength)
+	if err != nil {
+		return err
+	}
+	if wrapped, ok := parsed.(*hashpassword.WrappedHash); ok {
+		if hash, err = hashpassword.WrapHash(hash, max(wrapped.Iterations, hashpassword.DefaultWrapIterations)); err != nil {
+			return err
+		}
+	}
+	return a.print(hashResult{Hash: hash}, hash)
+}
+
+func (a *app) generate(args []string) error {
+	fs := a.flags("generate", "")
+	opts := passwordgen.DefaultPasswordOptions()
+	count := fs.Int("count", 1, "number of passwords")
+	fs.IntVar(&opts.Length, "length", opts.Length, "password `length`")
+	fs.BoolVar(&opts.UseUpper, "upper", true, "use upper-case letters")
+	fs.BoolVar(&opts.UseLower, "lower", true, "use lower-case letters")
+	fs.BoolVar(&opts.UseNumbers, "numbers", true, "use digits")
+	fs.BoolVar(&opts.UseSpecial, "special", true, "use special characters")
+	fs.Float64Var(&opts.MinEntropyBits, "min-entropy", 0, "refuse options giving fewer than `bits` of entropy")
+	rulesFlag := fs.String("rules", "", "generate for a passwordrules `descriptor` instead of the character set flags")
+	withHash := fs.Bool("hash", false, "also print a hash of each password")
+	secret := secretFlags(fs, "", defaultSecretEnv)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	if *count < 1 {
+		return withStatus(exitUsage, fmt.Errorf("--count must be positive, got %d", *count))
+	}
+	var rules *passwordgen.PasswordRules
+	if *rulesFlag != "" {
+		var err error
+		if rules, err = passwordgen.ParsePasswordRules(*rulesFlag); err != nil {
+			return withStatus(exitUsage, err)
+		}
+	} else if opts.Length < 1 {
+		return withStatus(exitUsage, fmt.Errorf("--length must be positive, got %d", opts.Length))
+	} else if bits, err := opts.EntropyBits(); err != nil {
+		return withStatus(exitUsage, err)
+	} else if opts.MinEntropyBits > 0 && bits < opts.MinEntropyBits {
+		return withStatus(exitUsage, fmt.Errorf("options give %.1f bits of entropy, below --min-entropy %.1f", bits, opts.MinEntropyBits))
+	}
+	var key string
+	if *withHash {
+		var err error
+		if key, err = a.secret(secret); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < *count; i++ {
+		var generated *passwordgen.GeneratedPassword
+		var err error
+		if rules != nil {
+			generated, err = passwordgen.GeneratePasswordForRulesWithEntropy(rules, opts.MinEntropyBits)
+		} else {
+			generated, err = passwordgen.GeneratePasswordWithEntropy(opts)
+		}
+		var entropyErr *passwordgen.InsufficientEntropyError
+		if errors.As(err, &entropyErr) {
+			return withStatus(exitUsage, err)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := generateResult{
+			Password:    generated.Password,
+			EntropyBits: generated.EntropyBits,
+			CharsetSize: generated.CharsetSize,
+		}
+		text := result.Password
+		if *withHash {
+			if result.Hash, err = hashpassword.HashPassword(result.Password, key); err != nil {
+				return err
+			}
+			text += "\t" + result.Hash
+		}
+		if err := a.print(result, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *app) info(args []string) error {
+	fs := a.flags("info", "")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	info := hashpassword.GetAlgorithmInfo()
+	keys := make([]string, 0, len(info))
+	for k := range info {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var text strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&text, "%s: %v\n", k, info[k])
+	}
+	return a.print(info, text.String())
+}
+
+func (a *app) migrate(args []string) error {
+	fs := a.flags("migrate", "[FILE]")
+	format := fs.String("format", "", "input `format`, csv or jsonl (default from the file extension, else csv)")
+	wrap := fs.Bool("wrap", false, "wrap legacy hmac_sha256 hashes in PBKDF2 and write the rows out")
+	iterations := fs.Int("iterations", hashpassword.DefaultWrapIterations, "PBKDF2 `iterations` for --wrap")
+	workers := fs.Int("workers", 0, "number of goroutines wrapping hashes (default the number of CPUs)")
+	output := fs.String("output", "", "write the rows to `file` instead of standard output")
+	fs.Usage = func() {
+		fmt.Fprint(a.stderr, "usage: hashpw migrate [flags] [FILE]\n\n"+
+			"Reads user_id,hash rows as CSV or JSONL from FILE or standard input and\n"+
+			"reports the hashes by algorithm, parameters and encoding, listing\n"+
+			"malformed rows by line. With --wrap, legacy hashes are wrapped in PBKDF2\n"+
+			"and the rows written out; the report then goes to standard error when\n"+
+			"the rows go to standard output.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	positional, err := parse(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	opts := migrate.Options{Wrap: *wrap, Iterations: *iterations, Workers: *workers}
+	if *format == "" && len(positional) == 1 {
+		switch strings.ToLower(filepath.Ext(positional[0])) {
+		case ".jsonl", ".ndjson":
+			opts.Format = migrate.FormatJSONL
+		}
+	} else if *format != "" {
+		if opts.Format, err = migrate.ParseFormat(*format); err != nil {
+			return withStatus(exitUsage, err)
+		}
+	}
+	if *wrap && (*iterations < hashpassword.MinWrapIterations || *iterations > hashpassword.MaxWrapIterations) {
+		return withStatus(exitUsage, fmt.Errorf("--iterations must be between %d and %d, got %d",
+			hashpassword.MinWrapIterations, hashpassword.MaxWrapIterations, *iterations))
+	}
+	if *output != "" && !*wrap {
+		return withStatus(exitUsage, errors.New("--output requires --wrap"))
+	}
+
+	var in io.Reader = a.stdin
+	if len(positional) == 1 && positional[0] != "-" {
+		f, err := os.Open(positional[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	var out io.Writer
+	var outFile *os.File
+	reportTo := a.stdout
+	if *wrap && *output != "" {
+		if outFile, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer outFile.Close()
+		out = outFile
+	} else if *wrap {
+		out = a.stdout
+		reportTo = a.stderr
+	}
+
+	report, err := migrate.Run(in, out, opts)
+	if err != nil {
+		return err
+	}
+	if outFile != nil {
+		if err := outFile.Close(); err != nil {
+			return err
+		}
+	}
+
+	result := migrateResult{
+		Rows:          report.Rows,
+		Classes:       []classResult{},
+		Wrapped:       report.Wrapped,
+		Malformed:     report.Malformed,
+		MalformedRows: report.MalformedRows,
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "rows: %d\n", report.Rows)
+	for _, c := range report.Counts() {
+		result.Classes = append(result.Classes, classResult{
+			Algorithm: c.Class.Algorithm,
+			Params:    c.Class.Params,
+			Encoding:  c.Class.Encoding,
+			Count:     c.Count,
+		})
+		fmt.Fprintf(&text, "  %s: %d\n", c.Class, c.Count)
+	}
+	if *wrap {
+		fmt.Fprintf(&text, "wrapped: %d\n", report.Wrapped)
+	}
+	fmt.Fprintf(&text, "malformed: %d\n", report.Malformed)
+	for _, m := range report.MalformedRows {
+		if m.UserID != "" {
+			fmt.Fprintf(&text, "  line %d (user %s): %s\n", m.Line, m.UserID, m.Reason)
+		} else {
+			fmt.Fprintf(&text, "  line %d: %s\n", m.Line, m.Reason)
+		}
+	}
+	if n := report.Malformed - len(report.MalformedRows); n > 0 {
+		fmt.Fprintf(&text, "  ... and %d more\n", n)
+	}
+
+	if err := a.printTo(reportTo, result, text.String()); err != nil {
+		return err
+	}
+	if report.Malformed > 0 {
+		return withStatus(exitMalformed, fmt.Errorf("%d of %d rows are malformed", report.Malformed, report.Rows))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// secretSource is where a command finds a secret key
+type secretSource struct {
+	env  string
+	file string
+}
+
+// secretFlags registers --<prefix>secret-env and --<prefix>secret-file
+func secretFlags(fs *flag.FlagSet, prefix, defaultEnv string) *secretSource {
+	s := &secretSource{}
+	fs.StringVar(&s.env, prefix+"secret-env", defaultEnv, "read the "+prefix+"secret key from environment variable `name`")
+	fs.StringVar(&s.file, prefix+"secret-file", "", "read the "+prefix+"secret key from `file` instead")
+	return s
+}
+
+// secret returns the key from the file if one was given, and from the
+// environment otherwise. A trailing newline in the file is ignored.
+func (a *app) secret(s *secretSource) (string, error) {
+	if s.file != "" {
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			return "", withStatus(exitUsage, fmt.Errorf("reading secret key: %w", err))
+		}
+		key := strings.TrimRight(string(data), "\r\n")
+		if key == "" {
+			return "", withStatus(exitUsage, fmt.Errorf("secret key file %s is empty", s.file))
+		}
+		return key, nil
+	}
+
+	key := a.getenv(s.env)
+	if key == "" {
+		return "", withStatus(exitUsage, fmt.Errorf("no secret key: set $%s or use --secret-file", s.env))
+	}
+	return key, nil
+}
+
+// password reads one password: from a prompt without echo when standard
+// input is a terminal, otherwise the next line of standard input. With
+// confirm, a terminal user must type it twice.
+func (a *app) password(prompt string, confirm bool) (string, error) {
+	if a.terminal == nil {
+		line, err := a.readLine()
+		if errors.Is(err, io.EOF) {
+			return "", withStatus(exitUsage, errors.New("no password on standard input"))
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading password: %w", err)
+		}
+		if line == "" {
+			return "", withStatus(exitUsage, errors.New("password cannot be empty"))
+		}
+		return line, nil
+	}
+
+	password, err := a.prompt(prompt)
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", withStatus(exitUsage, errors.New("password cannot be empty"))
+	}
+	if confirm {
+		again, err := a.prompt("Confirm " + strings.ToLower(prompt[:1]) + prompt[1:])
+		if err != nil {
+			return "", err
+		}
+		if again != password {
+			return "", withStatus(exitMismatch, errors.New("passwords do not match"))
+		}
+	}
+	return password, nil
+}
+
+// prompt asks for a line on the terminal with echo turned off
+func (a *app) prompt(prompt string) (string, error) {
+	fmt.Fprint(a.stderr, prompt)
+	line, err := readPasswordNoEcho(a.terminal)
+	fmt.Fprintln(a.stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return line, nil
+}
+
+// readLine returns the next line of standard input without its line ending.
+// io.EOF is returned only when there is no more input at all.
+func (a *app) readLine() (string, error) {
+	line, err := a.stdin.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,206 @@
+// Command hashpw hashes and verifies passwords in the hmac_sha256 format of
+// package hashpassword, including hashes wrapped in PBKDF2.
+//
+//	hashpw hash [--salt-length N]          hash a password
+//	hashpw verify HASH                     check a password against HASH
+//	hashpw inspect [HASH...]               describe hashes without the secret key
+//	hashpw rehash HASH                     verify, then hash again (e.g. under a new key)
+//	hashpw generate [--count N] [--hash]   generate random passwords
+//	hashpw info                            print algorithm information
+//	hashpw migrate [--wrap] [FILE]         classify (and wrap) an export of hashes
+//
+// Passwords are read from standard input, one line each, or prompted for
+// without echo when standard input is a terminal. The secret key is never
+// taken from the command line: it comes from the environment variable named
+// by --secret-env (HASHPW_SECRET by default) or from the file given with
+// --secret-file. Every command accepts --json to print JSON objects, one per
+// line, instead of text.
+//
+// The exit status is 0 on success, 1 when a password does not match, 2 for
+// invalid usage or a missing secret key, 3 for a malformed hash and 4 for any
+// other error.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Exit statuses
+const (
+	exitOK        = 0
+	exitMismatch  = 1
+	exitUsage     = 2
+	exitMalformed = 3
+	exitError     = 4
+)
+
+// defaultSecretEnv is the environment variable holding the secret key
+// unless --secret-env names another
+const defaultSecretEnv = "HASHPW_SECRET"
+
+const usage = `usage: hashpw <command> [flags] [arguments]
+
+Commands:
+  hash      hash a password
+  verify    check a password against a hash
+  inspect   describe hashes without the secret key
+  rehash    verify a password against a hash and hash it again
+  generate  generate random passwords
+  info      print algorithm information
+  migrate   classify the hashes of a CSV or JSONL export and wrap legacy ones
+
+Run "hashpw <command> -h" for the flags of a command.
+`
+
+// commands maps each subcommand to its implementation
+var commands = map[string]func(*app, []string) error{
+	"hash":     (*app).hash,
+	"verify":   (*app).verify,
+	"inspect":  (*app).inspect,
+	"rehash":   (*app).rehash,
+	"generate": (*app).generate,
+	"info":     (*app).info,
+	"migrate":  (*app).migrate,
+}
+
+// app holds the streams and environment of one invocation, so tests can
+// run commands without a process
+type app struct {
+	stdin  *bufio.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	// terminal is standard input when it is a terminal; passwords are then
+	// prompted for without echo
+	terminal *os.File
+
+	json bool
+}
+
+// statusError carries an exit status with the error to report
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// withStatus returns err with the exit status to use for it
+func withStatus(status int, err error) error {
+	return &statusError{status: status, err: err}
+}
+
+// errSilent ends a command with a status but no message, e.g. a mismatch
+// that has already been printed
+var errSilent = errors.New("")
+
+func main() {
+	a := &app{
+		stdin:  bufio.NewReader(os.Stdin),
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	if isTerminal(os.Stdin) {
+		a.terminal = os.Stdin
+	}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// run dispatches to the command named by args[0] and returns the exit status
+func (a *app) run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(a.stderr, usage)
+		return exitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(a.stdout, usage)
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "hashpw: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	err := cmd(a, args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	status := exitError
+	var e *statusError
+	if errors.As(err, &e) {
+		status = e.status
+	}
+	if !errors.Is(err, errSilent) {
+		fmt.Fprintf(a.stderr, "hashpw %s: %v\n", args[0], err)
+	}
+	return status
+}
+
+// flags returns a FlagSet for the command with the --json flag every
+// command has
+func (a *app) flags(name, arguments string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.BoolVar(&a.json, "json", false, "print JSON instead of text")
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: hashpw %s [flags] %s\n\nFlags:\n", name, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args, allowing flags after the positional arguments, and
+// returns the positional arguments. A usage error is returned if their
+// number is outside [min, max]; max < 0 means no limit.
+func parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			// The FlagSet has already printed the error and usage
+			return nil, withStatus(exitUsage, errSilent)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, withStatus(exitUsage, errSilent)
+	}
+	return positional, nil
+}
+
+// print writes v as one line of JSON with --json, and text otherwise
+func (a *app) print(v any, text string) error {
+	return a.printTo(a.stdout, v, text)
+}
+
+// printTo is print writing to w instead of standard output
+func (a *app) printTo(w io.Writer, v any, text string) error {
+	if a.json {
+		return json.NewEncoder(w).Encode(v)
+	}
+	_, err := fmt.Fprintln(w, strings.TrimSuffix(text, "\n"))
+	return err
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hashpassword "github.com/example/hashpassword"
+)
+
+const testSecret = "test-secret-key-at-least-32-bytes-long!"
+
+// runHashpw runs hashpw with args, stdin and environment variables,
+// returning its output and exit status
+func runHashpw(env map[string]string, stdin string, args ...string) (stdout, stderr string, status int) {
+	var out, errOut bytes.Buffer
+	a := &app{
+		stdin:  bufio.NewReader(strings.NewReader(stdin)),
+		stdout: &out,
+		stderr: &errOut,
+		getenv: func(name string) string { return env[name] },
+	}
+	status = a.run(args)
+	return out.String(), errOut.String(), status
+}
+
+var testEnv = map[string]string{defaultSecretEnv: testSecret}
+
+func TestHashAndVerify(t *testing.T) {
+	stdout, stderr, status := runHashpw(testEnv, "hunter2\n", "hash")
+	hash := strings.TrimSpace(stdout)
+	if status != exitOK || !strings.HasPrefix(hash, hashpassword.AlgorithmIdentifier+"$") {
+		t.Fatalf("hash = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+	if ok, err := hashpassword.VerifyPassword("hunter2", hash, testSecret); !ok || err != nil {
+		t.Fatalf("hash output does not verify: %v, %v", ok, err)
+	}
+
+	if stdout, _, status := runHashpw(testEnv, "hunter2\n", "verify", hash); status != exitOK || stdout != "match\n" {
+		t.Errorf("verify correct password = %q, status %d", stdout, status)
+	}
+	if stdout, _, status := runHashpw(testEnv, "hunter3", "verify", hash, "--json"); status != exitMismatch || stdout != `{"match":false,"needs_rehash":false}`+"\n" {
+		t.Errorf("verify wrong password = %q, status %d", stdout, status)
+	}
+	other := map[string]string{"OTHER_SECRET": "another-secret"}
+	if _, _, status := runHashpw(other, "hunter2\n", "verify", "--secret-env", "OTHER_SECRET", hash); status != exitMismatch {
+		t.Errorf("verify with another secret key status = %d, want %d", status, exitMismatch)
+	}
+}
+
+func TestHashOptions(t *testing.T) {
+	stdout, _, status := runHashpw(testEnv, "pw\r\n", "hash", "--salt-length", "16", "--json")
+	var result hashResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil || status != exitOK {
+		t.Fatalf("hash --json = %q, status %d, err %v", stdout, status, err)
+	}
+	parsed, err := hashpassword.ParseHash(result.Hash)
+	if err != nil || len(parsed.Salt) != 16 {
+		t.Fatalf("hash --salt-length 16 produced %q: %v", result.Hash, err)
+	}
+	// The line ending is not part of the password
+	if ok, _ := hashpassword.VerifyPassword("pw", result.Hash, testSecret); !ok {
+		t.Error("hash kept the line ending in the password")
+	}
+
+	// verify reports a short salt as needing a rehash
+	if stdout, _, _ := runHashpw(testEnv, "pw\n", "verify", "--json", result.Hash); !strings.Contains(stdout, `"needs_rehash":true`) {
+		t.Errorf("verify --json of a 16-byte salt hash = %q, want needs_rehash", stdout)
+	}
+}
+
+func TestSecretFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(testSecret+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, status := runHashpw(nil, "hunter2\n", "hash", "--secret-file", path)
+	if status != exitOK {
+		t.Fatalf("hash --secret-file status %d, stderr %q", status, stderr)
+	}
+	if ok, _ := hashpassword.VerifyPassword("hunter2", strings.TrimSpace(stdout), testSecret); !ok {
+		t.Error("--secret-file key kept its trailing newline")
+	}
+}
+
+func TestRehash(t *testing.T) {
+	hash, _ := hashpassword.HashPasswordWithSaltLength("hunter2", testSecret, 16)
+	env := map[string]string{defaultSecretEnv: testSecret, "NEW_SECRET": "rotated-secret-key"}
+
+	stdout, stderr, status := runHashpw(env, "hunter2\n", "rehash", hash, "--new-secret-env", "NEW_SECRET")
+	if status != exitOK {
+		t.Fatalf("rehash status %d, stderr %q", status, stderr)
+	}
+	newHash := strings.TrimSpace(stdout)
+	if ok, _ := hashpassword.VerifyPassword("hunter2", newHash, "rotated-secret-key"); !ok {
+		t.Errorf("rehash output %q does not verify under the new key", newHash)
+	}
+	if parsed, _ := hashpassword.ParseHash(newHash); parsed.NeedsRehash() {
+		t.Error("rehash kept the short salt")
+	}
+
+	if stdout, _, status := runHashpw(env, "wrong\n", "rehash", hash); status != exitMismatch || stdout != "" {
+		t.Errorf("rehash with the wrong password = %q, status %d", stdout, status)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	hash, _ := hashpassword.HashPassword("hunter2", testSecret)
+	stdout, stderr, status := runHashpw(nil, hash+"\n\nnot-a-hash\n", "inspect", "--json")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if status != exitMalformed || len(lines) != 2 || !strings.Contains(stderr, "1 of 2") {
+		t.Fatalf("inspect = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+
+	var valid, invalid inspectResult
+	json.Unmarshal([]byte(lines[0]), &valid)
+	json.Unmarshal([]byte(lines[1]), &invalid)
+	if !valid.Valid || valid.SaltBytes != 32 || valid.DigestBytes != 32 || valid.NeedsRehash {
+		t.Errorf("inspect of a valid hash = %+v", valid)
+	}
+	if invalid.Valid || invalid.Error == "" {
+		t.Errorf("inspect of an invalid hash = %+v", invalid)
+	}
+
+	stdout, _, status = runHashpw(nil, "", "inspect", hash)
+	if status != exitOK || stdout != "algorithm=hmac_sha256 salt_bytes=32 digest_bytes=32 needs_rehash=false\n" {
+		t.Errorf("inspect HASH = %q, status %d", stdout, status)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	stdout, stderr, status := runHashpw(testEnv, "", "generate", "--count", "3", "--length", "20", "--special=false", "--hash", "--json")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if status != exitOK || len(lines) != 3 {
+		t.Fatalf("generate = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+	for _, line := range lines {
+		var result generateResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("generate line %q: %v", line, err)
+		}
+		if len(result.Password) != 20 || result.CharsetSize != 62 || result.EntropyBits < 100 {
+			t.Errorf("generate result = %+v", result)
+		}
+		if ok, _ := hashpassword.VerifyPassword(result.Password, result.Hash, testSecret); !ok {
+			t.Errorf("generated hash does not verify for %q", result.Password)
+		}
+	}
+
+	stdout, _, status = runHashpw(nil, "", "generate", "--rules", "minlength: 12; maxlength: 12; required: digit; required: upper")
+	if password := strings.TrimSpace(stdout); status != exitOK || len(password) != 12 {
+		t.Errorf("generate --rules = %q, status %d", stdout, status)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	stdout, _, status := runHashpw(nil, "", "info", "--json")
+	var info map[string]any
+	if err := json.Unmarshal([]byte(stdout), &info); err != nil || status != exitOK {
+		t.Fatalf("info --json = %q, status %d, err %v", stdout, status, err)
+	}
+	if info["algorithm"] != hashpassword.AlgorithmIdentifier {
+		t.Errorf("info algorithm = %v", info["algorithm"])
+	}
+
+	stdout, _, _ = runHashpw(nil, "", "info")
+	if !strings.HasPrefix(stdout, "algorithm: hmac_sha256\n") {
+		t.Errorf("info = %q", stdout)
+	}
+}
+
+func TestWrappedHash(t *testing.T) {
+	hash, _ := hashpassword.HashPassword("hunter2", testSecret)
+	wrapped, _ := hashpassword.WrapHash(hash, hashpassword.MinWrapIterations)
+
+	if stdout, stderr, status := runHashpw(testEnv, "hunter2\n", "verify", "--json", wrapped); status != exitOK || stdout != `{"match":true,"needs_rehash":true}`+"\n" {
+		t.Errorf("verify of a wrapped hash = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+	if _, _, status := runHashpw(testEnv, "hunter3\n", "verify", wrapped); status != exitMismatch {
+		t.Errorf("verify of a wrapped hash with the wrong password status = %d", status)
+	}
+
+	stdout, _, status := runHashpw(nil, "", "inspect", wrapped)
+	want := "algorithm=wrap(pbkdf2,hmac_sha256) iterations=1000 salt_bytes=16 hmac_salt_bytes=32 digest_bytes=32 needs_rehash=true\n"
+	if status != exitOK || stdout != want {
+		t.Errorf("inspect of a wrapped hash = %q, status %d", stdout, status)
+	}
+
+	// rehash keeps the hash wrapped, raising the iterations to the default
+	stdout, stderr, status := runHashpw(testEnv, "hunter2\n", "rehash", wrapped)
+	newHash := strings.TrimSpace(stdout)
+	parsed, err := hashpassword.ParseWrappedHash(newHash)
+	if status != exitOK || err != nil || parsed.Iterations != hashpassword.DefaultWrapIterations {
+		t.Fatalf("rehash of a wrapped hash = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+	if ok, _ := hashpassword.VerifyPassword("hunter2", newHash, testSecret); !ok {
+		t.Error("rehash of a wrapped hash does not verify")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	hash, _ := hashpassword.HashPassword("hunter2", testSecret)
+	input := "user_id,hash\n1," + hash + "\n2,hmac_sha256$abc\n"
+
+	stdout, stderr, status := runHashpw(nil, input, "migrate")
+	want := "rows: 2\n  hmac_sha256 base64url: 1\nmalformed: 1\n  line 3 (user 2): invalid hash format\n"
+	if status != exitMalformed || stdout != want || !strings.Contains(stderr, "1 of 2 rows") {
+		t.Errorf("migrate = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+
+	// With --wrap the rows go to standard output and the report to standard error
+	stdout, stderr, status = runHashpw(nil, input, "migrate", "--wrap", "--iterations", "1000", "--json")
+	rows := strings.Split(strings.TrimSpace(stdout), "\n")
+	var report migrateResult
+	json.Unmarshal([]byte(strings.SplitN(stderr, "\n", 2)[0]), &report)
+	if status != exitMalformed || len(rows) != 3 || report.Wrapped != 1 || report.Rows != 2 {
+		t.Fatalf("migrate --wrap = %q, status %d, stderr %q", stdout, status, stderr)
+	}
+	wrapped := strings.TrimPrefix(rows[1], `1,"`)
+	if _, err := hashpassword.ParseWrappedHash(strings.TrimSuffix(wrapped, `"`)); err != nil {
+		t.Errorf("migrate --wrap row %q: %v", rows[1], err)
+	}
+
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "users.jsonl"), filepath.Join(dir, "wrapped.jsonl")
+	os.WriteFile(in, []byte(`{"user_id":1,"hash":"`+hash+`"}`+"\n"), 0o600)
+	stdout, _, status = runHashpw(nil, "", "migrate", "--wrap", "--iterations", "1000", "--output", out, in)
+	data, _ := os.ReadFile(out)
+	if status != exitOK || !strings.HasPrefix(stdout, "rows: 1\n") || !strings.Contains(string(data), hashpassword.WrappedAlgorithmIdentifier) {
+		t.Errorf("migrate --output = %q, status %d, wrote %q", stdout, status, data)
+	}
+}
+
+func TestExitStatuses(t *testing.T) {
+	hash, _ := hashpassword.HashPassword("hunter2", testSecret)
+	tests := []struct {
+		name    string
+		env     map[string]string
+		stdin   string
+		args    []string
+		status  int
+		wantErr string
+	}{
+		{"no command", nil, "", nil, exitUsage, "usage: hashpw"},
+		{"unknown command", nil, "", []string{"crack"}, exitUsage, `unknown command "crack"`},
+		{"unknown flag", testEnv, "", []string{"hash", "--cost", "12"}, exitUsage, "flag provided but not defined"},
+		{"missing hash", testEnv, "pw\n", []string{"verify"}, exitUsage, "usage: hashpw verify"},
+		{"missing secret", nil, "pw\n", []string{"hash"}, exitUsage, "set $HASHPW_SECRET"},
+		{"missing secret file", nil, "pw\n", []string{"hash", "--secret-file", "/nonexistent/secret"}, exitUsage, "reading secret key"},
+		{"no password", testEnv, "", []string{"hash"}, exitUsage, "no password"},
+		{"empty password", testEnv, "\n", []string{"verify", hash}, exitUsage, "password cannot be empty"},
+		{"short salt", testEnv, "pw\n", []string{"hash", "--salt-length", "8"}, exitUsage, "at least 16"},
+		{"malformed hash", testEnv, "pw\n", []string{"verify", "hmac_sha256$abc"}, exitMalformed, "invalid hash format"},
+		{"malformed wrapped hash", testEnv, "pw\n", []string{"verify", "wrap(pbkdf2,hmac_sha256)$i=10$a$b$c"}, exitMalformed, "iterations must be between"},
+		{"other algorithm", testEnv, "pw\n", []string{"verify", "bcrypt$a$b"}, exitMalformed, "invalid algorithm"},
+		{"malformed before secret", nil, "pw\n", []string{"rehash", "garbage"}, exitMalformed, "invalid hash format"},
+		{"mismatch", testEnv, "wrong\n", []string{"verify", hash}, exitMismatch, ""},
+		{"no character sets", nil, "", []string{"generate", "--upper=false", "--lower=false", "--numbers=false", "--special=false"}, exitUsage, "character set"},
+		{"low entropy", nil, "", []string{"generate", "--length", "4", "--min-entropy", "64"}, exitUsage, "below --min-entropy"},
+		{"bad rules", nil, "", []string{"generate", "--rules", "minlength 5"}, exitUsage, "invalid password rules"},
+		{"unknown format", nil, "", []string{"migrate", "--format", "xml"}, exitUsage, "unknown format"},
+		{"few iterations", nil, "", []string{"migrate", "--wrap", "--iterations", "10"}, exitUsage, "--iterations must be between"},
+		{"many iterations", nil, "", []string{"migrate", "--wrap", "--iterations", "2000000000"}, exitUsage, "--iterations must be between"},
+		{"output without wrap", nil, "", []string{"migrate", "--output", "out.csv"}, exitUsage, "--output requires --wrap"},
+		{"missing export", nil, "", []string{"migrate", "/nonexistent/users.csv"}, exitError, "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, status := runHashpw(tt.env, tt.stdin, tt.args...)
+			if status != tt.status || !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("hashpw %v = status %d, stderr %q; want status %d, stderr containing %q",
+					tt.args, status, stderr, tt.status, tt.wantErr)
+			}
+		})
+	}
+}
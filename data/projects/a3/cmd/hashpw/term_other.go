@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// isTerminal reports false: without terminal control on this platform,
+// passwords are always read as lines of standard input
+func isTerminal(*os.File) bool {
+	return false
+}
+
+func readPasswordNoEcho(*os.File) (string, error) {
+	return "", errors.New("reading a password without echo is not supported on this platform")
+}
@@ -66,52 +66,84 @@ func HashPasswordWithSaltLength(password, secretKey string, saltLength int) (str
 }
 
 // VerifyPassword verifies a password against a hash using the secret key.
-// Returns true if the password matches, false otherwise.
+// Returns true if the password matches, false otherwise. Hashes wrapped in
+// PBKDF2 by WrapHash are verified by recomputing the inner HMAC first.
 func VerifyPassword(password, hash, secretKey string) (bool, error) {
 	if err := validateInputs(password, secretKey); err != nil {
 		return false, err
 	}
 
-	if hash == "" {
-		return false, ErrInvalidHash
+	if algorithm, _, _ := strings.Cut(hash, Separator); algorithm == WrappedAlgorithmIdentifier {
+		wrapped, err := ParseWrappedHash(hash)
+		if err != nil {
+			return false, err
+		}
+		return wrapped.verify(password, secretKey), nil
 	}
 
-	// Parse the hash string
-	parts := strings.Split(hash, Separator)
-	if len(parts) != 3 {
-		return false, ErrInvalidHash
+	parsed, err := ParseHash(hash)
+	if err != nil {
+		return false, err
 	}
 
-	algorithm := parts[0]
-	saltB64 := parts[1]
-	expectedHashB64 := parts[2]
+	// Compute HMAC with provided password
+	computedHash := computeHMAC(password, secretKey, parsed.Salt)
 
-	// Verify algorithm identifier
-	if algorithm != AlgorithmIdentifier {
-		return false, ErrInvalidAlgorithm
+	// Constant-time comparison to prevent timing attacks
+	if subtle.ConstantTimeCompare(computedHash, parsed.Digest) == 1 {
+		return true, nil
 	}
 
-	// Decode salt
-	salt, err := base64.URLEncoding.DecodeString(saltB64)
-	if err != nil {
-		return false, fmt.Errorf("failed to decode salt: %w", err)
+	return false, nil
+}
+
+// PasswordHash is a parsed hash string of the form algorithm$salt$hash
+type PasswordHash struct {
+	Algorithm string
+	Salt      []byte
+	Digest    []byte
+}
+
+// String returns the hash in its serialized form
+func (h *PasswordHash) String() string {
+	return h.Algorithm + Separator + base64.URLEncoding.EncodeToString(h.Salt) +
+		Separator + base64.URLEncoding.EncodeToString(h.Digest)
+}
+
+// NeedsRehash reports whether the hash should be replaced the next time the
+// password is known, because its salt is shorter than DefaultSaltLength
+func (h *PasswordHash) NeedsRehash() bool {
+	return len(h.Salt) < DefaultSaltLength
+}
+
+// ParseHash parses a hash produced by HashPassword without verifying it.
+// It returns ErrInvalidAlgorithm for another algorithm and an error wrapping
+// ErrInvalidHash for anything else that is not a well-formed hash.
+func ParseHash(hash string) (*PasswordHash, error) {
+	parts := strings.Split(hash, Separator)
+	if len(parts) != 3 {
+		return nil, ErrInvalidHash
+	}
+	if parts[0] != AlgorithmIdentifier {
+		return nil, ErrInvalidAlgorithm
 	}
 
-	// Decode expected hash
-	expectedHash, err := base64.URLEncoding.DecodeString(expectedHashB64)
+	salt, err := base64.URLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return false, fmt.Errorf("failed to decode hash: %w", err)
+		return nil, fmt.Errorf("%w: failed to decode salt: %w", ErrInvalidHash, err)
 	}
-
-	// Compute HMAC with provided password
-	computedHash := computeHMAC(password, secretKey, salt)
-
-	// Constant-time comparison to prevent timing attacks
-	if subtle.ConstantTimeCompare(computedHash, expectedHash) == 1 {
-		return true, nil
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("%w: empty salt", ErrInvalidHash)
+	}
+	digest, err := base64.URLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode hash: %w", ErrInvalidHash, err)
+	}
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("%w: hash is %d bytes, want %d", ErrInvalidHash, len(digest), sha256.Size)
 	}
 
-	return false, nil
+	return &PasswordHash{Algorithm: parts[0], Salt: salt, Digest: digest}, nil
 }
 
 // computeHMAC computes HMAC-SHA256 of password + salt using secret key
@@ -140,11 +172,14 @@ func validateInputs(password, secretKey string) error {
 // GetAlgorithmInfo returns information about the algorithm used
 func GetAlgorithmInfo() map[string]interface{} {
 	return map[string]interface{}{
-		"algorithm":       AlgorithmIdentifier,
-		"hash_function":   "SHA-256",
-		"hmac":            true,
-		"default_salt_length": DefaultSaltLength,
-		"hash_format":     "algorithm$salt$hash",
-		"encoding":        "base64url",
+		"algorithm":               AlgorithmIdentifier,
+		"hash_function":           "SHA-256",
+		"hmac":                    true,
+		"default_salt_length":     DefaultSaltLength,
+		"hash_format":             "algorithm$salt$hash",
+		"encoding":                "base64url",
+		"wrapped_algorithm":       WrappedAlgorithmIdentifier,
+		"wrapped_hash_format":     "algorithm$i=iterations$salt$hmac_salt$hash",
+		"wrap_default_iterations": DefaultWrapIterations,
 	}
 }
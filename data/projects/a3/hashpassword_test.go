@@ -1,6 +1,7 @@
 package hashpassword
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -111,6 +112,43 @@ func TestInvalidHashFormat(t *testing.T) {
 	}
 }
 
+func TestParseHash(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	hash, _ := HashPasswordWithSaltLength("password", secretKey, 16)
+
+	parsed, err := ParseHash(hash)
+	if err != nil {
+		t.Fatalf("ParseHash returned error: %v", err)
+	}
+	if parsed.Algorithm != AlgorithmIdentifier || len(parsed.Salt) != 16 || len(parsed.Digest) != 32 {
+		t.Errorf("ParseHash = %+v", parsed)
+	}
+	if parsed.String() != hash {
+		t.Errorf("String() = %s, want %s", parsed.String(), hash)
+	}
+	if !parsed.NeedsRehash() {
+		t.Error("16-byte salt should need rehash")
+	}
+
+	invalid := []string{
+		"",
+		"hmac_sha256$abc",
+		"hmac_sha256$!!!!$" + strings.Repeat("A", 43) + "=",
+		"hmac_sha256$$" + strings.Repeat("A", 43) + "=",
+		"hmac_sha256$" + strings.Repeat("A", 44) + "$QUFB",
+		// a2 hashes use unpadded standard base64
+		"hmac_sha256$" + strings.Repeat("A", 43) + "$" + strings.Repeat("A", 43),
+	}
+	for _, h := range invalid {
+		if _, err := ParseHash(h); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("ParseHash(%q) error = %v, want ErrInvalidHash", h, err)
+		}
+	}
+	if _, err := ParseHash("bcrypt$salt$hash"); err != ErrInvalidAlgorithm {
+		t.Errorf("ParseHash with another algorithm error = %v, want ErrInvalidAlgorithm", err)
+	}
+}
+
 func TestCustomSaltLength(t *testing.T) {
 	secretKey := "test-secret-key-at-least-32-bytes-long!"
 	password := "testPassword123"
@@ -0,0 +1,18 @@
+// Package cryptorand holds the crypto/rand helpers shared by the packages of
+// this module.
+package cryptorand
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Index returns a uniformly distributed random integer in [0, n)
+// using crypto/rand
+func Index(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	hashpassword "github.com/example/hashpassword"
+)
+
+// Encodings of the base64 fields of a hash
+const (
+	// EncodingBase64URL is the padded URL alphabet written by this module
+	EncodingBase64URL = "base64url"
+	// EncodingBase64Raw is the unpadded standard alphabet written by the
+	// earlier a2 implementation of hmac_sha256
+	EncodingBase64Raw = "base64-raw"
+	// EncodingBase64URLRaw is the unpadded URL alphabet of Node's base64url,
+	// written by the JavaScript implementations
+	EncodingBase64URLRaw = "base64url-raw"
+	// EncodingBcrypt is bcrypt's own base64 variant
+	EncodingBcrypt = "bcrypt"
+)
+
+// Algorithm identifiers of the JavaScript implementations
+const (
+	algorithmPBKDF2 = "pbkdf2_sha256"
+	algorithmScrypt = "scrypt"
+	algorithmBcrypt = "bcrypt"
+)
+
+// hmacEncodings are tried in order for hmac_sha256 hashes. The 32-byte
+// digest makes padded and unpadded forms distinguishable.
+var hmacEncodings = []struct {
+	name     string
+	encoding *base64.Encoding
+}{
+	{EncodingBase64URL, base64.URLEncoding},
+	{EncodingBase64Raw, base64.RawStdEncoding},
+	{EncodingBase64URLRaw, base64.RawURLEncoding},
+}
+
+// bcryptPattern matches $2a$, $2b$, $2x$ and $2y$ hashes: a two-digit cost,
+// 22 characters of salt and 31 of hash
+var bcryptPattern = regexp.MustCompile(`^\$2[abxy]\$(\d\d)\$[./A-Za-z0-9]{53}$`)
+
+// Class describes the kind of a stored hash
+type Class struct {
+	Algorithm string
+	// Params holds the cost parameters, e.g. "i=600000"; empty when the
+	// algorithm has none
+	Params   string
+	Encoding string
+}
+
+// String returns the non-empty fields separated by spaces, e.g.
+// "hmac_sha256 base64-raw"
+func (c Class) String() string {
+	fields := []string{c.Algorithm}
+	if c.Params != "" {
+		fields = append(fields, c.Params)
+	}
+	return strings.Join(append(fields, c.Encoding), " ")
+}
+
+// Legacy reports whether hashes of the class are plain hmac_sha256, which
+// can be wrapped in PBKDF2
+func (c Class) Legacy() bool {
+	return c.Algorithm == hashpassword.AlgorithmIdentifier
+}
+
+// Classify identifies the algorithm, parameters and encoding of a hash. It
+// recognizes hmac_sha256 hashes in the encodings of this module (a3) and the
+// earlier a2 implementation, wrapped hashes, and the pbkdf2_sha256, scrypt
+// and bcrypt hashes of the JavaScript implementations. Anything else
+// returns an error wrapping hashpassword.ErrInvalidHash.
+func Classify(hash string) (Class, error) {
+	class, _, err := classify(hash)
+	return class, err
+}
+
+// classify is Classify, also returning the parsed hash of legacy classes
+func classify(hash string) (Class, *hashpassword.PasswordHash, error) {
+	if match := bcryptPattern.FindStringSubmatch(hash); match != nil {
+		return Class{Algorithm: algorithmBcrypt, Params: "cost=" + match[1], Encoding: EncodingBcrypt}, nil, nil
+	}
+
+	parts := strings.Split(hash, hashpassword.Separator)
+	switch parts[0] {
+	case hashpassword.AlgorithmIdentifier:
+		return classifyHMAC(parts)
+	case hashpassword.WrappedAlgorithmIdentifier:
+		wrapped, err := hashpassword.ParseWrappedHash(hash)
+		if err != nil {
+			return Class{}, nil, err
+		}
+		params := "i=" + strconv.Itoa(wrapped.Iterations)
+		return Class{Algorithm: parts[0], Params: params, Encoding: EncodingBase64URL}, nil, nil
+	case algorithmPBKDF2:
+		return classifyJS(parts, "i")
+	case algorithmScrypt:
+		return classifyJS(parts, "N")
+	case "":
+		return Class{}, nil, fmt.Errorf("%w: empty hash", hashpassword.ErrInvalidHash)
+	}
+	return Class{}, nil, fmt.Errorf("%w: unrecognized algorithm %q", hashpassword.ErrInvalidHash, truncate(parts[0], 32))
+}
+
+// classifyHMAC identifies the encoding of algorithm$salt$hash
+func classifyHMAC(parts []string) (Class, *hashpassword.PasswordHash, error) {
+	if len(parts) != 3 {
+		return Class{}, nil, hashpassword.ErrInvalidHash
+	}
+	for _, e := range hmacEncodings {
+		salt, err := e.encoding.DecodeString(parts[1])
+		if err != nil || len(salt) == 0 {
+			continue
+		}
+		digest, err := e.encoding.DecodeString(parts[2])
+		if err != nil || len(digest) != 32 {
+			continue
+		}
+		parsed := &hashpassword.PasswordHash{Algorithm: parts[0], Salt: salt, Digest: digest}
+		return Class{Algorithm: parts[0], Encoding: e.name}, parsed, nil
+	}
+	return Class{}, nil, fmt.Errorf("%w: salt and hash are not base64 in a known encoding", hashpassword.ErrInvalidHash)
+}
+
+// classifyJS checks algorithm$cost$salt$hash as written by the JavaScript
+// implementations, naming the cost param
+func classifyJS(parts []string, param string) (Class, *hashpassword.PasswordHash, error) {
+	if len(parts) != 4 {
+		return Class{}, nil, hashpassword.ErrInvalidHash
+	}
+	cost, err := strconv.Atoi(parts[1])
+	if err != nil || cost < 1 {
+		return Class{}, nil, fmt.Errorf("%w: invalid cost %q", hashpassword.ErrInvalidHash, truncate(parts[1], 32))
+	}
+	for _, field := range parts[2:] {
+		if decoded, err := base64.RawURLEncoding.DecodeString(field); err != nil || len(decoded) == 0 {
+			return Class{}, nil, fmt.Errorf("%w: salt and hash are not base64url", hashpassword.ErrInvalidHash)
+		}
+	}
+	return Class{Algorithm: parts[0], Params: param + "=" + parts[1], Encoding: EncodingBase64URLRaw}, nil, nil
+}
+
+// truncate shortens s for error messages, which end up in reports
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	hashpassword "github.com/example/hashpassword"
+)
+
+const testSecret = "test-secret-key-at-least-32-bytes-long!"
+
+// a2Hash hashes like the earlier a2 implementation: the same HMAC, with
+// unpadded standard base64
+func a2Hash(password string, salt []byte) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(password))
+	mac.Write(salt)
+	return "hmac_sha256$" + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestClassify(t *testing.T) {
+	a3, _ := hashpassword.HashPassword("password", testSecret)
+	a3Short, _ := hashpassword.HashPasswordWithSaltLength("password", testSecret, 16)
+	wrapped, _ := hashpassword.WrapHash(a3, hashpassword.MinWrapIterations)
+	raw := base64.RawURLEncoding
+
+	tests := []struct {
+		hash string
+		want Class
+	}{
+		{a3, Class{"hmac_sha256", "", EncodingBase64URL}},
+		{a3Short, Class{"hmac_sha256", "", EncodingBase64URL}},
+		{a2Hash("password", make([]byte, 32)), Class{"hmac_sha256", "", EncodingBase64Raw}},
+		// 48 bytes of salt encode the same padded and unpadded
+		{a2Hash("password", make([]byte, 48)), Class{"hmac_sha256", "", EncodingBase64Raw}},
+		{wrapped, Class{"wrap(pbkdf2,hmac_sha256)", "i=1000", EncodingBase64URL}},
+		{"pbkdf2_sha256$300000$" + raw.EncodeToString(make([]byte, 16)) + "$" + raw.EncodeToString(make([]byte, 32)),
+			Class{"pbkdf2_sha256", "i=300000", EncodingBase64URLRaw}},
+		{"scrypt$16384$" + raw.EncodeToString([]byte("salt-salt-salt!!")) + "$" + raw.EncodeToString(make([]byte, 64)),
+			Class{"scrypt", "N=16384", EncodingBase64URLRaw}},
+		{"$2b$10$N9qo8uLOickgx2ZMRZoMye" + strings.Repeat("a", 31),
+			Class{"bcrypt", "cost=10", EncodingBcrypt}},
+	}
+
+	for _, tt := range tests {
+		got, err := Classify(tt.hash)
+		if err != nil || got != tt.want {
+			t.Errorf("Classify(%q) = %v, %v, want %v", tt.hash, got, err, tt.want)
+		}
+	}
+
+	// A padded hash whose digest is 32 bytes is never taken for a2
+	if c, _ := Classify(a3); c.Encoding != EncodingBase64URL || !c.Legacy() {
+		t.Errorf("Classify(a3 hash) = %v", c)
+	}
+	if c, _ := Classify(wrapped); c.Legacy() {
+		t.Error("wrapped hash classified as legacy")
+	}
+}
+
+func TestClassifyMalformed(t *testing.T) {
+	a3, _ := hashpassword.HashPassword("password", testSecret)
+	malformed := []string{
+		"",
+		"hmac_sha256",
+		"hmac_sha256$abc$def",
+		a3[:len(a3)-4],
+		strings.Replace(a3, "$", "$!", 1),
+		"argon2id$v=19$m=65536$salt$hash",
+		"pbkdf2_sha256$many$c2FsdA$aGFzaA",
+		"pbkdf2_sha256$1000$c2FsdA",
+		"scrypt$16384$$aGFzaA",
+		"$2b$10$tooshort",
+		"wrap(pbkdf2,hmac_sha256)$i=10$a$b$c",
+	}
+	for _, h := range malformed {
+		if c, err := Classify(h); !errors.Is(err, hashpassword.ErrInvalidHash) {
+			t.Errorf("Classify(%q) = %v, %v, want ErrInvalidHash", h, c, err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package migrate classifies and upgrades exported password hashes in bulk.
+// An export is CSV (user_id,hash) or JSONL; Run reads it as a stream,
+// counts the hashes by algorithm, parameters and encoding, lists malformed
+// rows by line number and, optionally, wraps every legacy hmac_sha256 hash
+// in PBKDF2 (see hashpassword.WrappedHash) without the passwords, writing
+// the export back with the new hashes. hashpassword.VerifyPassword accepts
+// the wrapped hashes, so the export can be loaded back as it is.
+package migrate
+
+import (
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+
+	hashpassword "github.com/example/hashpassword"
+)
+
+// DefaultMaxMalformed is how many malformed rows a Report lists when
+// Options.MaxMalformed is 0
+const DefaultMaxMalformed = 1000
+
+// recordsPerWorker is the batch size per worker; batches keep the output
+// in input order while workers wrap in parallel
+const recordsPerWorker = 64
+
+// Options configures Run
+type Options struct {
+	Format Format
+	// Wrap wraps legacy hmac_sha256 hashes in PBKDF2 with Iterations
+	// (hashpassword.DefaultWrapIterations when 0)
+	Wrap       bool
+	Iterations int
+	// Workers is the number of goroutines wrapping hashes;
+	// runtime.GOMAXPROCS(0) when 0 or less
+	Workers int
+	// MaxMalformed bounds Report.MalformedRows; DefaultMaxMalformed when 0,
+	// none when negative
+	MaxMalformed int
+}
+
+// MalformedRow is a row whose user or hash could not be read
+type MalformedRow struct {
+	Line   int    `json:"line"`
+	UserID string `json:"user_id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ClassCount is the number of hashes of one class
+type ClassCount struct {
+	Class Class
+	Count int
+}
+
+// Report summarizes a Run
+type Report struct {
+	// Rows is the number of data rows, including malformed ones
+	Rows int
+	// Classes counts the well-formed hashes by class, before wrapping
+	Classes map[Class]int
+	// Wrapped is the number of legacy hashes wrapped
+	Wrapped int
+	// Malformed is the number of malformed rows; MalformedRows lists the
+	// first ones
+	Malformed     int
+	MalformedRows []MalformedRow
+}
+
+// Counts returns the classes from the most to the least common
+func (r *Report) Counts() []ClassCount {
+	counts := make([]ClassCount, 0, len(r.Classes))
+	for class, n := range r.Classes {
+		counts = append(counts, ClassCount{Class: class, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Class.String() < counts[j].Class.String()
+	})
+	return counts
+}
+
+// Run reads an export from r and returns its Report. If w is not nil the
+// export is written to it, with legacy hashes wrapped when opts.Wrap is set.
+// Malformed rows are written back unchanged, except CSV rows that could not
+// be parsed at all and JSONL lines longer than 1 MiB, which are only
+// reported. The error is for failures to
+// read r or write w; the Report then covers the rows processed so far.
+func Run(r io.Reader, w io.Writer, opts Options) (*Report, error) {
+	if opts.Iterations == 0 {
+		opts.Iterations = hashpassword.DefaultWrapIterations
+	}
+	if opts.Wrap && (opts.Iterations < hashpassword.MinWrapIterations || opts.Iterations > hashpassword.MaxWrapIterations) {
+		return nil, hashpassword.ErrWrapIterations
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.MaxMalformed == 0 {
+		opts.MaxMalformed = DefaultMaxMalformed
+	}
+
+	report := &Report{Classes: make(map[Class]int)}
+	reader := newReader(opts.Format, r)
+	var writer recordWriter
+	if w != nil {
+		writer = newWriter(opts.Format, w)
+	}
+
+	batch := make([]*record, 0, recordsPerWorker*opts.Workers)
+	for done := false; !done; {
+		batch = batch[:0]
+		for len(batch) < cap(batch) {
+			rec, err := reader.next()
+			if err == io.EOF {
+				done = true
+				break
+			}
+			if err != nil {
+				return report, err
+			}
+			batch = append(batch, rec)
+		}
+
+		if err := processBatch(batch, opts); err != nil {
+			return report, err
+		}
+		for _, rec := range batch {
+			report.add(rec, opts)
+			if writer != nil {
+				if err := writer.write(rec); err != nil {
+					return report, err
+				}
+			}
+		}
+	}
+
+	if writer != nil {
+		if err := writer.flush(); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// processBatch classifies the records and wraps legacy hashes, spreading
+// the records over opts.Workers goroutines
+func processBatch(batch []*record, opts Options) error {
+	var wg sync.WaitGroup
+	errs := make([]error, opts.Workers)
+	for worker := 0; worker < opts.Workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := worker; i < len(batch); i += opts.Workers {
+				if err := processRecord(batch[i], opts); err != nil {
+					errs[worker] = err
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processRecord classifies one record, setting rec.class or rec.err, and
+// wraps its hash if it is legacy. The returned error is for failures other
+// than malformed input, such as the random source failing.
+func processRecord(rec *record, opts Options) error {
+	if rec.err != nil {
+		return nil
+	}
+	class, parsed, err := classify(rec.hash)
+	if err != nil {
+		rec.err = err
+		return nil
+	}
+	rec.class = class
+	if !opts.Wrap || !class.Legacy() {
+		return nil
+	}
+
+	wrapped, err := parsed.Wrap(opts.Iterations)
+	if err != nil {
+		return err
+	}
+	rec.hash = wrapped.String()
+	rec.changed = true
+	return nil
+}
+
+// add counts a processed record
+func (r *Report) add(rec *record, opts Options) {
+	if rec.err == errHeader {
+		return
+	}
+	r.Rows++
+	if rec.err != nil {
+		r.Malformed++
+		if len(r.MalformedRows) < opts.MaxMalformed {
+			r.MalformedRows = append(r.MalformedRows, MalformedRow{
+				Line:   rec.line,
+				UserID: rec.userID,
+				Reason: rec.err.Error(),
+			})
+		}
+		return
+	}
+	r.Classes[rec.class]++
+	if rec.changed {
+		r.Wrapped++
+	}
+}
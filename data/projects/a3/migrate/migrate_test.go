@@ -0,0 +1,196 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	hashpassword "github.com/example/hashpassword"
+)
+
+// checkWrapped checks that wrapped is a wrap of the legacy hash: it keeps
+// the original salt and has a fresh PBKDF2 salt and digest
+func checkWrapped(t *testing.T, legacy, wrapped string, iterations int) {
+	t.Helper()
+	w, err := hashpassword.ParseWrappedHash(wrapped)
+	if err != nil {
+		t.Fatalf("ParseWrappedHash(%q): %v", wrapped, err)
+	}
+	_, inner, err := classify(legacy)
+	if err != nil {
+		t.Fatalf("classify(%q): %v", legacy, err)
+	}
+	if w.Iterations != iterations || !bytes.Equal(w.HMACSalt, inner.Salt) || bytes.Equal(w.Digest, inner.Digest) {
+		t.Errorf("wrap of %q = %+v", legacy, w)
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	a3, _ := hashpassword.HashPassword("one", testSecret)
+	a2 := a2Hash("two", []byte("0123456789abcdef0123456789abcdef"))
+	wrapped, _ := hashpassword.WrapHash(a3, hashpassword.MinWrapIterations)
+	input := strings.Join([]string{
+		"user_id,hash",
+		"1," + a3,
+		"2," + a2,
+		`3,"` + wrapped + `"`,
+		"4,not-a-hash",
+		"5",
+		`6,"unterminated`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	report, err := Run(strings.NewReader(input), &out, Options{Wrap: true, Iterations: hashpassword.MinWrapIterations})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if report.Rows != 6 || report.Wrapped != 2 || report.Malformed != 3 {
+		t.Errorf("report = %d rows, %d wrapped, %d malformed, want 6, 2, 3", report.Rows, report.Wrapped, report.Malformed)
+	}
+	wantClasses := map[Class]int{
+		{"hmac_sha256", "", EncodingBase64URL}:                    1,
+		{"hmac_sha256", "", EncodingBase64Raw}:                    1,
+		{"wrap(pbkdf2,hmac_sha256)", "i=1000", EncodingBase64URL}: 1,
+	}
+	if fmt.Sprint(report.Classes) != fmt.Sprint(wantClasses) {
+		t.Errorf("classes = %v, want %v", report.Classes, wantClasses)
+	}
+	var lines []int
+	for _, m := range report.MalformedRows {
+		lines = append(lines, m.Line)
+	}
+	if fmt.Sprint(lines) != "[5 6 7]" || report.MalformedRows[0].UserID != "4" {
+		t.Errorf("malformed rows = %+v, want lines 5, 6, 7", report.MalformedRows)
+	}
+
+	cr := csv.NewReader(&out)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v\n%s", err, out.String())
+	}
+	// The unparsable row is dropped; everything else is kept in order
+	if len(rows) != 6 || rows[0][1] != "hash" || rows[3][1] != wrapped || rows[4][1] != "not-a-hash" || len(rows[5]) != 1 {
+		t.Fatalf("output rows = %q", rows)
+	}
+	checkWrapped(t, a3, rows[1][1], hashpassword.MinWrapIterations)
+	checkWrapped(t, a2, rows[2][1], hashpassword.MinWrapIterations)
+
+	// Wrapped a2 and a3 hashes both verify with the original passwords
+	for password, hash := range map[string]string{"one": rows[1][1], "two": rows[2][1]} {
+		if ok, err := hashpassword.VerifyPassword(password, hash, testSecret); !ok || err != nil {
+			t.Errorf("VerifyPassword(%q, %q) = %v, %v", password, hash, ok, err)
+		}
+	}
+}
+
+func TestRunJSONL(t *testing.T) {
+	a3, _ := hashpassword.HashPassword("one", testSecret)
+	input := fmt.Sprintf(`{"user_id": 17, "hash": %q, "email": "a@example.com"}
+
+{"user_id":"u2","hash":"bogus"}
+not json
+{"hash": %q}
+{"user_id": 3, "hash": 42}
+`, a3, a3)
+
+	var out bytes.Buffer
+	report, err := Run(strings.NewReader(input), &out, Options{Format: FormatJSONL, Wrap: true, Iterations: hashpassword.MinWrapIterations, Workers: 3})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if report.Rows != 5 || report.Wrapped != 1 || report.Malformed != 4 {
+		t.Errorf("report = %d rows, %d wrapped, %d malformed, want 5, 1, 4", report.Rows, report.Wrapped, report.Malformed)
+	}
+	want := []MalformedRow{
+		{Line: 3, UserID: "u2"},
+		{Line: 4, Reason: "not a JSON object"},
+		{Line: 5, Reason: `missing "user_id"`},
+		{Line: 6, UserID: "3", Reason: `"hash" is not a string`},
+	}
+	for i, m := range report.MalformedRows {
+		if m.Line != want[i].Line || m.UserID != want[i].UserID || (want[i].Reason != "" && m.Reason != want[i].Reason) {
+			t.Errorf("malformed row %d = %+v, want %+v", i, m, want[i])
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 || lines[1] != `{"user_id":"u2","hash":"bogus"}` || lines[2] != "not json" {
+		t.Fatalf("output = %q", lines)
+	}
+	var first struct {
+		UserID json.Number `json:"user_id"`
+		Hash   string      `json:"hash"`
+		Email  string      `json:"email"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first.UserID != "17" || first.Email != "a@example.com" {
+		t.Fatalf("first output line = %s, %v", lines[0], err)
+	}
+	checkWrapped(t, a3, first.Hash, hashpassword.MinWrapIterations)
+	// Only the hash is replaced; the other fields keep their order and spacing
+	if want := fmt.Sprintf(`{"user_id": 17, "hash": %q, "email": "a@example.com"}`, first.Hash); lines[0] != want {
+		t.Errorf("first output line = %s, want %s", lines[0], want)
+	}
+}
+
+func TestRunReportOnly(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 1500; i++ {
+		fmt.Fprintf(&input, "%d,bad-%d\n", i, i)
+	}
+	for i := 0; i < 300; i++ {
+		h, _ := hashpassword.HashPassword("pw", testSecret)
+		fmt.Fprintf(&input, "u%d,%s\n", i, h)
+	}
+
+	report, err := Run(strings.NewReader(input.String()), nil, Options{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if report.Rows != 1800 || report.Malformed != 1500 || len(report.MalformedRows) != DefaultMaxMalformed || report.Wrapped != 0 {
+		t.Errorf("report = %d rows, %d malformed, %d listed, %d wrapped", report.Rows, report.Malformed, len(report.MalformedRows), report.Wrapped)
+	}
+	counts := report.Counts()
+	if len(counts) != 1 || counts[0].Count != 300 || counts[0].Class.String() != "hmac_sha256 base64url" {
+		t.Errorf("counts = %v", counts)
+	}
+
+	report, _ = Run(strings.NewReader(input.String()), nil, Options{MaxMalformed: -1})
+	if len(report.MalformedRows) != 0 || report.Malformed != 1500 {
+		t.Errorf("MaxMalformed -1 listed %d of %d rows", len(report.MalformedRows), report.Malformed)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(strings.NewReader(""), nil, Options{Wrap: true, Iterations: 10}); !errors.Is(err, hashpassword.ErrWrapIterations) {
+		t.Errorf("Run with 10 iterations error = %v, want ErrWrapIterations", err)
+	}
+
+	// A line longer than maxLineLength is one malformed row, not a failure
+	h, _ := hashpassword.HashPassword("pw", testSecret)
+	long := `{"user_id": 1, "hash": "` + strings.Repeat("a", maxLineLength) + `"}`
+	input := long + "\n" + fmt.Sprintf(`{"user_id": 2, "hash": %q}`, h) + "\n" + long
+	var out bytes.Buffer
+	report, err := Run(strings.NewReader(input), &out, Options{Format: FormatJSONL})
+	if err != nil {
+		t.Fatalf("Run with a long line returned error: %v", err)
+	}
+	if report.Rows != 3 || report.Malformed != 2 || report.MalformedRows[0].Line != 1 || report.MalformedRows[1].Line != 3 {
+		t.Errorf("report = %d rows, %d malformed %+v, want 3 rows with lines 1 and 3 malformed", report.Rows, report.Malformed, report.MalformedRows)
+	}
+	if want := fmt.Sprintf(`{"user_id": 2, "hash": %q}`, h) + "\n"; out.String() != want {
+		t.Errorf("output = %.100q, want only the short line", out.String())
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+	if f, err := ParseFormat("NDJSON"); err != nil || f != FormatJSONL {
+		t.Errorf("ParseFormat(NDJSON) = %v, %v", f, err)
+	}
+}
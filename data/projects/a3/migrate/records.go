@@ -0,0 +1,284 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the layout of an export
+type Format int
+
+const (
+	// FormatCSV is user_id,hash rows with an optional header row
+	FormatCSV Format = iota
+	// FormatJSONL is one JSON object per line with "user_id" and "hash"
+	// fields; other fields are carried over
+	FormatJSONL
+)
+
+// maxLineLength bounds a JSONL line; longer lines are reported as malformed
+const maxLineLength = 1 << 20
+
+// ParseFormat parses "csv" or "jsonl" (also "ndjson")
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, want csv or jsonl", s)
+}
+
+func (f Format) String() string {
+	if f == FormatJSONL {
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// record is one row of an export
+type record struct {
+	line   int
+	userID string
+	hash   string
+	// err is set for a row that could not be read
+	err error
+	// class is the class of a well-formed hash, and changed is set when
+	// hash has been replaced
+	class   Class
+	changed bool
+
+	// fields holds a CSV row, for writing the record back
+	fields []string
+	// raw is the line of a JSONL row, written back unless changed, and
+	// hashStart:hashEnd the span of its "hash" value
+	raw                []byte
+	hashStart, hashEnd int
+}
+
+// recordReader reads the records of an export. Malformed rows are returned
+// as records with err set; the error return is for failures to read at all.
+type recordReader interface {
+	next() (*record, error)
+}
+
+// recordWriter writes records back, replacing their hashes
+type recordWriter interface {
+	write(r *record) error
+	flush() error
+}
+
+func newReader(format Format, r io.Reader) recordReader {
+	if format == FormatJSONL {
+		return &jsonlReader{r: bufio.NewReaderSize(r, 64*1024)}
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &csvReader{r: cr}
+}
+
+func newWriter(format Format, w io.Writer) recordWriter {
+	if format == FormatJSONL {
+		return &jsonlWriter{w: bufio.NewWriter(w)}
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// csvReader reads user_id,hash rows, recognizing a header row
+type csvReader struct {
+	r       *csv.Reader
+	started bool
+}
+
+func (c *csvReader) next() (*record, error) {
+	fields, err := c.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &record{line: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := c.r.FieldPos(0)
+	first := !c.started
+	c.started = true
+	if first && isHeader(fields) {
+		return &record{line: line, fields: fields, err: errHeader}, nil
+	}
+
+	r := &record{line: line, fields: fields}
+	if len(fields) != 2 {
+		r.err = fmt.Errorf("expected 2 fields, got %d", len(fields))
+		return r, nil
+	}
+	r.userID, r.hash = fields[0], fields[1]
+	return r, nil
+}
+
+// errHeader marks the header row, which is written back but not counted
+var errHeader = errors.New("header row")
+
+func isHeader(fields []string) bool {
+	return len(fields) == 2 &&
+		strings.EqualFold(strings.TrimSpace(fields[0]), "user_id") &&
+		strings.EqualFold(strings.TrimSpace(fields[1]), "hash")
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) write(r *record) error {
+	if r.fields == nil {
+		// A row csv.Reader could not parse has nothing to write back
+		return nil
+	}
+	if r.changed {
+		r.fields[1] = r.hash
+	}
+	return c.w.Write(r.fields)
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlReader reads one object per line, skipping blank lines
+type jsonlReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (j *jsonlReader) next() (*record, error) {
+	for {
+		text, tooLong, err := j.readLine()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", j.line+1, err)
+		}
+		j.line++
+		if tooLong {
+			return &record{line: j.line, err: fmt.Errorf("line longer than %d bytes", maxLineLength)}, nil
+		}
+		text = bytes.TrimSpace(text)
+		if len(text) == 0 {
+			continue
+		}
+		r := &record{line: j.line, raw: text}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(text, &object); err != nil || object == nil {
+			r.err = errors.New("not a JSON object")
+			return r, nil
+		}
+		userID, ok := object["user_id"]
+		if !ok {
+			r.err = errors.New(`missing "user_id"`)
+			return r, nil
+		}
+		// Numeric IDs are kept in their JSON form
+		if err := json.Unmarshal(userID, &r.userID); err != nil {
+			r.userID = string(userID)
+		}
+		hash, ok := object["hash"]
+		if !ok {
+			r.err = errors.New(`missing "hash"`)
+			return r, nil
+		}
+		if err := json.Unmarshal(hash, &r.hash); err != nil {
+			r.err = errors.New(`"hash" is not a string`)
+			return r, nil
+		}
+		r.hashStart, r.hashEnd = valueSpan(text, "hash")
+		return r, nil
+	}
+}
+
+// readLine returns the next line, or tooLong if it exceeds maxLineLength,
+// in which case the rest of it is skipped instead of buffered
+func (j *jsonlReader) readLine() (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := j.r.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(bytes.TrimRight(line, "\r\n")) > maxLineLength {
+				line, tooLong = nil, true
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && (len(line) > 0 || tooLong) {
+			// The last line has no newline; EOF comes with the next call
+			err = nil
+		}
+		return line, tooLong, err
+	}
+}
+
+// valueSpan returns the byte range of the value of key in the JSON object
+// line, the last one if the key repeats as it is for json.Unmarshal
+func valueSpan(line []byte, key string) (start, end int) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		return 0, 0
+	}
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			break
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			break
+		}
+		if name == key {
+			end = int(dec.InputOffset())
+			start = end - len(value)
+		}
+	}
+	return start, end
+}
+
+type jsonlWriter struct {
+	w *bufio.Writer
+}
+
+// write writes the line back as it was read, with only the "hash" value
+// replaced when it changed, so the other fields keep their order and form
+func (j *jsonlWriter) write(r *record) error {
+	if r.raw == nil {
+		// A line too long to read has nothing to write back
+		return nil
+	}
+	if r.changed {
+		hash, err := json.Marshal(r.hash)
+		if err != nil {
+			return err
+		}
+		j.w.Write(r.raw[:r.hashStart])
+		j.w.Write(hash)
+		j.w.Write(r.raw[r.hashEnd:])
+	} else {
+		j.w.Write(r.raw)
+	}
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonlWriter) flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,304 @@
+package hashpassword
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTPAlgorithm is the HMAC hash function used for HOTP/TOTP codes
+type OTPAlgorithm string
+
+const (
+	// OTPAlgorithmSHA1 is the default and most widely supported algorithm
+	OTPAlgorithmSHA1 OTPAlgorithm = "SHA1"
+	// OTPAlgorithmSHA256 is allowed by RFC 6238
+	OTPAlgorithmSHA256 OTPAlgorithm = "SHA256"
+	// OTPAlgorithmSHA512 is allowed by RFC 6238
+	OTPAlgorithmSHA512 OTPAlgorithm = "SHA512"
+)
+
+const (
+	// DefaultOTPSecretLength is the default secret size in bytes (160 bits, as recommended by RFC 4226)
+	DefaultOTPSecretLength = 20
+	// MinOTPSecretLength is the shortest secret accepted in bytes (128 bits, the RFC 4226 minimum)
+	MinOTPSecretLength = 16
+	// DefaultOTPDigits is the default number of digits in a code
+	DefaultOTPDigits = 6
+	// DefaultOTPPeriod is the default TOTP time step in seconds
+	DefaultOTPPeriod = 30
+	// DefaultOTPSkew is the default number of steps accepted either side of the current one
+	DefaultOTPSkew = 1
+)
+
+var (
+	// ErrInvalidOTPSecret is returned when a secret is not valid base32 or
+	// shorter than MinOTPSecretLength bytes
+	ErrInvalidOTPSecret = errors.New("invalid OTP secret")
+	// ErrInvalidOTPConfig is returned for unsupported digits, periods or algorithms
+	ErrInvalidOTPConfig = errors.New("invalid OTP configuration")
+	// ErrInvalidOTPTime is returned for TOTP times before the Unix epoch,
+	// which have no RFC 6238 time step
+	ErrInvalidOTPTime = errors.New("TOTP time is before 1970")
+)
+
+// otpSecretEncoding is unpadded base32, the form used in otpauth:// URIs
+var otpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// digitsPower holds 10^digits for the supported code lengths
+var digitsPower = [...]uint32{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000}
+
+// OTPConfig configures HOTP (RFC 4226) and TOTP (RFC 6238) codes
+type OTPConfig struct {
+	// Digits is the code length, 6 to 9
+	Digits int
+	// Period is the TOTP time step in seconds; unused by HOTP
+	Period int
+	// Algorithm is the HMAC hash function
+	Algorithm OTPAlgorithm
+	// Skew is how many steps (TOTP) or counters ahead (HOTP) are also accepted
+	Skew int
+}
+
+// DefaultOTPConfig returns the configuration understood by common
+// authenticator apps: 6 digits, 30 second steps, SHA1 and a skew of one step
+func DefaultOTPConfig() OTPConfig {
+	return OTPConfig{
+		Digits:    DefaultOTPDigits,
+		Period:    DefaultOTPPeriod,
+		Algorithm: OTPAlgorithmSHA1,
+		Skew:      DefaultOTPSkew,
+	}
+}
+
+// validate checks the configuration and returns the hash constructor to use
+func (c OTPConfig) validate() (func() hash.Hash, error) {
+	if c.Digits < 6 || c.Digits >= len(digitsPower) {
+		return nil, fmt.Errorf("%w: digits must be between 6 and %d", ErrInvalidOTPConfig, len(digitsPower)-1)
+	}
+	if c.Period <= 0 {
+		return nil, fmt.Errorf("%w: period must be greater than 0", ErrInvalidOTPConfig)
+	}
+	if c.Skew < 0 {
+		return nil, fmt.Errorf("%w: skew cannot be negative", ErrInvalidOTPConfig)
+	}
+	switch c.Algorithm {
+	case OTPAlgorithmSHA1:
+		return sha1.New, nil
+	case OTPAlgorithmSHA256:
+		return sha256.New, nil
+	case OTPAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidOTPConfig, c.Algorithm)
+	}
+}
+
+// GenerateOTPSecret generates a random secret of DefaultOTPSecretLength bytes
+// and returns it as unpadded base32
+func GenerateOTPSecret() (string, error) {
+	return GenerateOTPSecretWithLength(DefaultOTPSecretLength)
+}
+
+// GenerateOTPSecretWithLength generates a random secret of length bytes
+// (at least MinOTPSecretLength) and returns it as unpadded base32
+func GenerateOTPSecretWithLength(length int) (string, error) {
+	if length < MinOTPSecretLength {
+		return "", fmt.Errorf("OTP secret length must be at least %d bytes", MinOTPSecretLength)
+	}
+
+	secret := make([]byte, length)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate OTP secret: %w", err)
+	}
+	return otpSecretEncoding.EncodeToString(secret), nil
+}
+
+// decodeOTPSecret decodes a base32 secret, ignoring case, spaces and padding,
+// and checks it is at least MinOTPSecretLength bytes
+func decodeOTPSecret(secret string) ([]byte, error) {
+	cleaned := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	cleaned = strings.TrimRight(cleaned, "=")
+	if cleaned == "" {
+		return nil, ErrInvalidOTPSecret
+	}
+
+	key, err := otpSecretEncoding.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOTPSecret, err)
+	}
+	if len(key) < MinOTPSecretLength {
+		return nil, fmt.Errorf("%w: %d bytes, want at least %d", ErrInvalidOTPSecret, len(key), MinOTPSecretLength)
+	}
+	return key, nil
+}
+
+// otpCode computes the RFC 4226 dynamically truncated code for a counter
+func otpCode(key []byte, counter uint64, digits int, newHash func() hash.Hash) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	h := hmac.New(newHash, key)
+	h.Write(msg[:])
+	sum := h.Sum(nil)
+
+	// Dynamic truncation: the low nibble of the last byte selects 4 bytes
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	code := strconv.FormatUint(uint64(binCode%digitsPower[digits]), 10)
+	return strings.Repeat("0", digits-len(code)) + code
+}
+
+// GenerateHOTP returns the RFC 4226 code for the base32 secret and counter
+func GenerateHOTP(secret string, counter uint64, cfg OTPConfig) (string, error) {
+	newHash, err := cfg.validate()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return otpCode(key, counter, cfg.Digits, newHash), nil
+}
+
+// VerifyHOTP checks code against counters counter..counter+cfg.Skew.
+// On success it returns the matched counter; callers must store
+// matched+1 as the next expected counter to prevent replay.
+func VerifyHOTP(code, secret string, counter uint64, cfg OTPConfig) (bool, uint64, error) {
+	newHash, err := cfg.validate()
+	if err != nil {
+		return false, 0, err
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		return false, 0, err
+	}
+	if len(code) != cfg.Digits {
+		return false, 0, nil
+	}
+
+	for i := 0; i <= cfg.Skew; i++ {
+		candidate := otpCode(key, counter+uint64(i), cfg.Digits, newHash)
+		// Constant-time comparison to prevent timing attacks
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(code)) == 1 {
+			return true, counter + uint64(i), nil
+		}
+	}
+	return false, 0, nil
+}
+
+// totpCounter returns the RFC 6238 time step containing t, or
+// ErrInvalidOTPTime if t is before the Unix epoch
+func totpCounter(t time.Time, period int) (uint64, error) {
+	if t.Unix() < 0 {
+		return 0, ErrInvalidOTPTime
+	}
+	return uint64(t.Unix()) / uint64(period), nil
+}
+
+// GenerateTOTP returns the RFC 6238 code for the base32 secret at time t.
+// Times before 1970 return ErrInvalidOTPTime.
+func GenerateTOTP(secret string, t time.Time, cfg OTPConfig) (string, error) {
+	if _, err := cfg.validate(); err != nil {
+		return "", err
+	}
+	counter, err := totpCounter(t, cfg.Period)
+	if err != nil {
+		return "", err
+	}
+	return GenerateHOTP(secret, counter, cfg)
+}
+
+// VerifyTOTP checks code against the time step containing t and cfg.Skew
+// steps either side of it to tolerate clock drift. Use VerifyTOTPStep to
+// reject a code that was already accepted.
+func VerifyTOTP(code, secret string, t time.Time, cfg OTPConfig) (bool, error) {
+	ok, _, err := VerifyTOTPStep(code, secret, t, cfg)
+	return ok, err
+}
+
+// VerifyTOTPStep is VerifyTOTP that also returns the matched time step.
+// RFC 6238 section 5.2 forbids accepting a code twice, so callers must
+// store the step and refuse later codes whose step is not greater than it.
+// Times before 1970 return ErrInvalidOTPTime.
+func VerifyTOTPStep(code, secret string, t time.Time, cfg OTPConfig) (bool, uint64, error) {
+	if _, err := cfg.validate(); err != nil {
+		return false, 0, err
+	}
+	counter, err := totpCounter(t, cfg.Period)
+	if err != nil {
+		return false, 0, err
+	}
+
+	start := counter
+	if start >= uint64(cfg.Skew) {
+		start -= uint64(cfg.Skew)
+	} else {
+		start = 0
+	}
+
+	window := cfg
+	window.Skew = int(counter-start) + cfg.Skew
+	return VerifyHOTP(code, secret, start, window)
+}
+
+// TOTPAuthURI builds an otpauth://totp/ URI for provisioning authenticator apps
+func TOTPAuthURI(issuer, account, secret string, cfg OTPConfig) (string, error) {
+	if _, err := cfg.validate(); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("period", strconv.Itoa(cfg.Period))
+	return otpAuthURI("totp", issuer, account, secret, cfg, params)
+}
+
+// HOTPAuthURI builds an otpauth://hotp/ URI for provisioning authenticator apps
+func HOTPAuthURI(issuer, account, secret string, counter uint64, cfg OTPConfig) (string, error) {
+	if _, err := cfg.validate(); err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Set("counter", strconv.FormatUint(counter, 10))
+	return otpAuthURI("hotp", issuer, account, secret, cfg, params)
+}
+
+// otpAuthURI builds the Key Uri Format shared by TOTP and HOTP
+func otpAuthURI(kind, issuer, account, secret string, cfg OTPConfig, params url.Values) (string, error) {
+	if account == "" {
+		return "", errors.New("account name cannot be empty")
+	}
+	if _, err := decodeOTPSecret(secret); err != nil {
+		return "", err
+	}
+
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+		params.Set("issuer", issuer)
+	}
+	params.Set("secret", strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secret, " ", "")), "="))
+	params.Set("algorithm", string(cfg.Algorithm))
+	params.Set("digits", strconv.Itoa(cfg.Digits))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     kind,
+		Path:     "/" + label,
+		RawQuery: strings.ReplaceAll(params.Encode(), "+", "%20"),
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,274 @@
+package hashpassword
+
+import (
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// base32Secret encodes an RFC test vector key the way it would be provisioned
+func base32Secret(key string) string {
+	return base32.StdEncoding.EncodeToString([]byte(key))
+}
+
+func TestGenerateHOTPRFC4226(t *testing.T) {
+	// RFC 4226 Appendix D
+	secret := base32Secret("12345678901234567890")
+	want := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, expected := range want {
+		code, err := GenerateHOTP(secret, uint64(counter), DefaultOTPConfig())
+		if err != nil {
+			t.Fatalf("GenerateHOTP() unexpected error = %v", err)
+		}
+		if code != expected {
+			t.Errorf("GenerateHOTP(counter=%d) = %s, want %s", counter, code, expected)
+		}
+	}
+}
+
+func TestGenerateTOTPRFC6238(t *testing.T) {
+	// RFC 6238 Appendix B
+	secrets := map[OTPAlgorithm]string{
+		OTPAlgorithmSHA1:   base32Secret("12345678901234567890"),
+		OTPAlgorithmSHA256: base32Secret("12345678901234567890123456789012"),
+		OTPAlgorithmSHA512: base32Secret("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	tests := []struct {
+		unix      int64
+		algorithm OTPAlgorithm
+		want      string
+	}{
+		{59, OTPAlgorithmSHA1, "94287082"},
+		{59, OTPAlgorithmSHA256, "46119246"},
+		{59, OTPAlgorithmSHA512, "90693936"},
+		{1111111109, OTPAlgorithmSHA1, "07081804"},
+		{1111111109, OTPAlgorithmSHA256, "68084774"},
+		{1111111109, OTPAlgorithmSHA512, "25091201"},
+		{1111111111, OTPAlgorithmSHA1, "14050471"},
+		{1111111111, OTPAlgorithmSHA256, "67062674"},
+		{1111111111, OTPAlgorithmSHA512, "99943326"},
+		{1234567890, OTPAlgorithmSHA1, "89005924"},
+		{1234567890, OTPAlgorithmSHA256, "91819424"},
+		{1234567890, OTPAlgorithmSHA512, "93441116"},
+		{2000000000, OTPAlgorithmSHA1, "69279037"},
+		{2000000000, OTPAlgorithmSHA256, "90698825"},
+		{2000000000, OTPAlgorithmSHA512, "38618901"},
+		{20000000000, OTPAlgorithmSHA1, "65353130"},
+		{20000000000, OTPAlgorithmSHA256, "77737706"},
+		{20000000000, OTPAlgorithmSHA512, "47863826"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algorithm)+"/"+tt.want, func(t *testing.T) {
+			cfg := DefaultOTPConfig()
+			cfg.Digits = 8
+			cfg.Algorithm = tt.algorithm
+
+			code, err := GenerateTOTP(secrets[tt.algorithm], time.Unix(tt.unix, 0), cfg)
+			if err != nil {
+				t.Fatalf("GenerateTOTP() unexpected error = %v", err)
+			}
+			if code != tt.want {
+				t.Errorf("GenerateTOTP(%d) = %s, want %s", tt.unix, code, tt.want)
+			}
+
+			ok, err := VerifyTOTP(tt.want, secrets[tt.algorithm], time.Unix(tt.unix, 0), cfg)
+			if err != nil || !ok {
+				t.Errorf("VerifyTOTP(%d) = %v, %v, want true", tt.unix, ok, err)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTPSkew(t *testing.T) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateOTPSecret() unexpected error = %v", err)
+	}
+	cfg := DefaultOTPConfig()
+	now := time.Unix(1700000000, 0)
+
+	code, err := GenerateTOTP(secret, now, cfg)
+	if err != nil {
+		t.Fatalf("GenerateTOTP() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		skew   int
+		want   bool
+	}{
+		{name: "Same step", offset: 0, skew: 0, want: true},
+		{name: "One step late within skew", offset: 30 * time.Second, skew: 1, want: true},
+		{name: "One step early within skew", offset: -30 * time.Second, skew: 1, want: true},
+		{name: "One step late without skew", offset: 30 * time.Second, skew: 0, want: false},
+		{name: "Two steps late with skew 1", offset: 60 * time.Second, skew: 1, want: false},
+		{name: "Two steps late with skew 2", offset: 60 * time.Second, skew: 2, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			c.Skew = tt.skew
+			ok, err := VerifyTOTP(code, secret, now.Add(tt.offset), c)
+			if err != nil {
+				t.Fatalf("VerifyTOTP() unexpected error = %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("VerifyTOTP() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTPStep(t *testing.T) {
+	secret := base32Secret("12345678901234567890")
+	cfg := DefaultOTPConfig()
+	cfg.Digits = 8
+
+	// 94287082 is the code of step 1 (t = 59), accepted one step later
+	ok, step, err := VerifyTOTPStep("94287082", secret, time.Unix(89, 0), cfg)
+	if err != nil || !ok || step != 1 {
+		t.Errorf("VerifyTOTPStep() = %v, %d, %v, want true, 1", ok, step, err)
+	}
+
+	if _, _, err := VerifyTOTPStep("94287082", secret, time.Unix(-1, 0), cfg); !errors.Is(err, ErrInvalidOTPTime) {
+		t.Errorf("VerifyTOTPStep() before 1970 error = %v, want ErrInvalidOTPTime", err)
+	}
+	if _, err := GenerateTOTP(secret, time.Unix(-30, 0), cfg); !errors.Is(err, ErrInvalidOTPTime) {
+		t.Errorf("GenerateTOTP() before 1970 error = %v, want ErrInvalidOTPTime", err)
+	}
+}
+
+func TestVerifyHOTPLookAhead(t *testing.T) {
+	secret := base32Secret("12345678901234567890")
+	cfg := DefaultOTPConfig()
+	cfg.Skew = 3
+
+	ok, matched, err := VerifyHOTP("969429", secret, 1, cfg)
+	if err != nil {
+		t.Fatalf("VerifyHOTP() unexpected error = %v", err)
+	}
+	if !ok || matched != 3 {
+		t.Errorf("VerifyHOTP() = %v, %d, want true, 3", ok, matched)
+	}
+
+	ok, _, _ = VerifyHOTP("969429", secret, 4, cfg)
+	if ok {
+		t.Error("VerifyHOTP() accepted a code for a counter behind the window")
+	}
+
+	ok, _, _ = VerifyHOTP("96942", secret, 3, cfg)
+	if ok {
+		t.Error("VerifyHOTP() accepted a truncated code")
+	}
+}
+
+func TestGenerateOTPSecret(t *testing.T) {
+	secret, err := GenerateOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateOTPSecret() unexpected error = %v", err)
+	}
+	if strings.Contains(secret, "=") {
+		t.Errorf("GenerateOTPSecret() = %q should not be padded", secret)
+	}
+	key, err := decodeOTPSecret(secret)
+	if err != nil {
+		t.Fatalf("decodeOTPSecret() unexpected error = %v", err)
+	}
+	if len(key) != DefaultOTPSecretLength {
+		t.Errorf("GenerateOTPSecret() decodes to %d bytes, want %d", len(key), DefaultOTPSecretLength)
+	}
+
+	// Lowercase and grouped secrets as typed by users must decode too
+	grouped := strings.ToLower(secret[:4] + " " + secret[4:])
+	if _, err := decodeOTPSecret(grouped); err != nil {
+		t.Errorf("decodeOTPSecret(%q) unexpected error = %v", grouped, err)
+	}
+
+	if _, err := GenerateOTPSecretWithLength(8); err == nil {
+		t.Error("GenerateOTPSecretWithLength(8) expected error")
+	}
+}
+
+func TestOTPErrors(t *testing.T) {
+	secret := base32Secret("12345678901234567890")
+
+	if _, err := GenerateHOTP("not base32!", 0, DefaultOTPConfig()); !errors.Is(err, ErrInvalidOTPSecret) {
+		t.Errorf("GenerateHOTP() error = %v, want ErrInvalidOTPSecret", err)
+	}
+	// 80 bits is below the RFC 4226 minimum of 128
+	if _, err := GenerateHOTP("JBSWY3DPEHPK3PXP", 0, DefaultOTPConfig()); !errors.Is(err, ErrInvalidOTPSecret) {
+		t.Errorf("GenerateHOTP() with an 80-bit secret error = %v, want ErrInvalidOTPSecret", err)
+	}
+
+	configs := []OTPConfig{
+		{Digits: 5, Period: 30, Algorithm: OTPAlgorithmSHA1},
+		{Digits: 10, Period: 30, Algorithm: OTPAlgorithmSHA1},
+		{Digits: 6, Period: 0, Algorithm: OTPAlgorithmSHA1},
+		{Digits: 6, Period: 30, Algorithm: "MD5"},
+		{Digits: 6, Period: 30, Algorithm: OTPAlgorithmSHA1, Skew: -1},
+	}
+	for _, cfg := range configs {
+		if _, err := GenerateTOTP(secret, time.Now(), cfg); !errors.Is(err, ErrInvalidOTPConfig) {
+			t.Errorf("GenerateTOTP(%+v) error = %v, want ErrInvalidOTPConfig", cfg, err)
+		}
+	}
+}
+
+func TestTOTPAuthURI(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+	uri, err := TOTPAuthURI("Example Co", "alice@example.com", secret, DefaultOTPConfig())
+	if err != nil {
+		t.Fatalf("TOTPAuthURI() unexpected error = %v", err)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) unexpected error = %v", uri, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("TOTPAuthURI() = %q, want otpauth://totp/", uri)
+	}
+	if u.Path != "/Example Co:alice@example.com" {
+		t.Errorf("TOTPAuthURI() label = %q", u.Path)
+	}
+	if strings.Contains(uri, "+") {
+		t.Errorf("TOTPAuthURI() = %q should encode spaces as %%20", uri)
+	}
+
+	query := u.Query()
+	expected := map[string]string{
+		"secret":    secret,
+		"issuer":    "Example Co",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+	for key, value := range expected {
+		if query.Get(key) != value {
+			t.Errorf("TOTPAuthURI() %s = %q, want %q", key, query.Get(key), value)
+		}
+	}
+
+	hotp, err := HOTPAuthURI("", "bob", secret, 7, DefaultOTPConfig())
+	if err != nil {
+		t.Fatalf("HOTPAuthURI() unexpected error = %v", err)
+	}
+	if !strings.HasPrefix(hotp, "otpauth://hotp/bob?") || !strings.Contains(hotp, "counter=7") {
+		t.Errorf("HOTPAuthURI() = %q", hotp)
+	}
+
+	if _, err := TOTPAuthURI("Example", "", secret, DefaultOTPConfig()); err == nil {
+		t.Error("TOTPAuthURI() expected error for empty account")
+	}
+}
@@ -0,0 +1,232 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// samplerBufferSize is how many random bytes are pulled from crypto/rand at once
+const samplerBufferSize = 4096
+
+// maxDuplicateRetries bounds how often a duplicate is regenerated before a
+// unique batch gives up
+const maxDuplicateRetries = 1000
+
+// ErrPasswordSpaceExhausted is returned when more unique passwords are
+// requested than the length and character sets can produce
+var ErrPasswordSpaceExhausted = errors.New("not enough distinct passwords for the requested count")
+
+// PasswordOptions describes the passwords produced by the batch and stream
+// generators
+type PasswordOptions struct {
+	Length     int
+	UseUpper   bool
+	UseLower   bool
+	UseNumbers bool
+	UseSpecial bool
+	// MinEntropyBits refuses generation with an *InsufficientEntropyError
+	// when the options yield fewer bits of entropy; 0 disables the check
+	MinEntropyBits float64
+}
+
+// DefaultPasswordOptions returns the options used by GeneratePasswordDefault:
+// 30 characters from all character sets
+func DefaultPasswordOptions() PasswordOptions {
+	return PasswordOptions{
+		Length:     30,
+		UseUpper:   true,
+		UseLower:   true,
+		UseNumbers: true,
+		UseSpecial: true,
+	}
+}
+
+// byteSampler draws uniform indexes from a buffered crypto/rand reader using
+// rejection sampling on single bytes, avoiding a big.Int per character.
+// It is not safe for concurrent use.
+type byteSampler struct {
+	r *bufio.Reader
+}
+
+func newByteSampler() *byteSampler {
+	return &byteSampler{r: bufio.NewReaderSize(rand.Reader, samplerBufferSize)}
+}
+
+// index returns a uniformly distributed integer in [0, n) for 0 < n <= 256
+func (s *byteSampler) index(n int) (int, error) {
+	// Reject bytes in the incomplete final bucket so every index is equally likely
+	limit := 256 - 256%n
+	for {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if int(b) < limit {
+			return int(b) % n, nil
+		}
+	}
+}
+
+// passwordGenerator produces passwords of a fixed shape from one sampler,
+// following the same rules as GeneratePasswordWithOptions
+type passwordGenerator struct {
+	sets    []string
+	charset string
+	length  int
+	sampler *byteSampler
+}
+
+func newPasswordGenerator(opts PasswordOptions) (*passwordGenerator, error) {
+	if opts.Length <= 0 {
+		return nil, errors.New("password length must be greater than 0")
+	}
+	sets, err := selectedSets(opts.UseUpper, opts.UseLower, opts.UseNumbers, opts.UseSpecial)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEntropy(coverageEntropy(opts.Length, sets), opts.MinEntropyBits); err != nil {
+		return nil, err
+	}
+	return &passwordGenerator{
+		sets:    sets,
+		charset: strings.Join(sets, ""),
+		length:  opts.Length,
+		sampler: newByteSampler(),
+	}, nil
+}
+
+func (g *passwordGenerator) next() (string, error) {
+	for attempt := 0; attempt < maxCoverageAttempts; attempt++ {
+		password := make([]byte, g.length)
+		for i := range password {
+			idx, err := g.sampler.index(len(g.charset))
+			if err != nil {
+				return "", err
+			}
+			password[i] = g.charset[idx]
+		}
+		if coversSets(string(password), g.sets) {
+			return string(password), nil
+		}
+	}
+	return "", errors.New("failed to generate a password containing every selected character set")
+}
+
+// capacity reports whether the generator can produce at least count
+// distinct passwords
+func (g *passwordGenerator) capacity(count int) bool {
+	// A small tolerance absorbs rounding when the space is exactly count
+	return coverageEntropy(g.length, g.sets)+1e-9 >= math.Log2(float64(count))
+}
+
+// nextUnique returns a password not yet recorded in seen and records it
+func (g *passwordGenerator) nextUnique(seen map[string]struct{}) (string, error) {
+	for attempt := 0; attempt < maxDuplicateRetries; attempt++ {
+		password, err := g.next()
+		if err != nil {
+			return "", err
+		}
+		if _, dup := seen[password]; !dup {
+			seen[password] = struct{}{}
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %d consecutive duplicates", ErrPasswordSpaceExhausted, maxDuplicateRetries)
+}
+
+// GeneratePasswordBatch generates count passwords sharing one buffered
+// random source. When unique is true no password repeats within the batch.
+func GeneratePasswordBatch(count int, opts PasswordOptions, unique bool) ([]string, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	gen, err := newPasswordGenerator(opts)
+	if err != nil {
+		return nil, err
+	}
+	if unique && !gen.capacity(count) {
+		return nil, ErrPasswordSpaceExhausted
+	}
+
+	var seen map[string]struct{}
+	if unique {
+		seen = make(map[string]struct{}, count)
+	}
+
+	passwords := make([]string, count)
+	for i := range passwords {
+		if unique {
+			passwords[i], err = gen.nextUnique(seen)
+		} else {
+			passwords[i], err = gen.next()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return passwords, nil
+}
+
+// StreamPasswords generates passwords in a background goroutine and sends
+// them on the returned channel until count passwords have been sent or ctx is
+// cancelled. A count of 0 streams until cancellation; in that case a unique
+// stream keeps every password sent so far in memory.
+// The error channel receives at most one error and is closed with the
+// password channel.
+func StreamPasswords(ctx context.Context, count int, opts PasswordOptions, unique bool) (<-chan string, <-chan error) {
+	out := make(chan string)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		if count < 0 {
+			errc <- errors.New("count cannot be negative")
+			return
+		}
+
+		gen, err := newPasswordGenerator(opts)
+		if err != nil {
+			errc <- err
+			return
+		}
+		if unique && count > 0 && !gen.capacity(count) {
+			errc <- ErrPasswordSpaceExhausted
+			return
+		}
+
+		var seen map[string]struct{}
+		if unique {
+			seen = make(map[string]struct{})
+		}
+
+		for sent := 0; count == 0 || sent < count; sent++ {
+			var password string
+			if unique {
+				password, err = gen.nextUnique(seen)
+			} else {
+				password, err = gen.next()
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			select {
+			case out <- password:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errc
+}
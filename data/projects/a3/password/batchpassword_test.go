@@ -0,0 +1,205 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePasswordBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		opts        PasswordOptions
+		unique      bool
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:  "Default options",
+			count: 100,
+			opts:  DefaultPasswordOptions(),
+		},
+		{
+			name:   "Unique default options",
+			count:  1000,
+			opts:   DefaultPasswordOptions(),
+			unique: true,
+		},
+		{
+			name:   "Unique batch filling the whole space",
+			count:  100,
+			opts:   PasswordOptions{Length: 2, UseNumbers: true},
+			unique: true,
+		},
+		{
+			name:        "Unique batch larger than the space",
+			count:       101,
+			opts:        PasswordOptions{Length: 2, UseNumbers: true},
+			unique:      true,
+			wantErr:     true,
+			errContains: "not enough distinct passwords",
+		},
+		{
+			name:   "Unique batch close to a space that requires both sets",
+			count:  500,
+			opts:   PasswordOptions{Length: 2, UseUpper: true, UseNumbers: true},
+			unique: true,
+		},
+		{
+			name:        "Unique batch larger than the space that requires both sets",
+			count:       521,
+			opts:        PasswordOptions{Length: 2, UseUpper: true, UseNumbers: true},
+			unique:      true,
+			wantErr:     true,
+			errContains: "not enough distinct passwords",
+		},
+		{
+			name:        "Zero count",
+			count:       0,
+			opts:        DefaultPasswordOptions(),
+			wantErr:     true,
+			errContains: "count must be greater than 0",
+		},
+		{
+			name:        "Zero length",
+			count:       10,
+			opts:        PasswordOptions{UseLower: true},
+			wantErr:     true,
+			errContains: "must be greater than 0",
+		},
+		{
+			name:        "No character sets",
+			count:       10,
+			opts:        PasswordOptions{Length: 10},
+			wantErr:     true,
+			errContains: "at least one character set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwords, err := GeneratePasswordBatch(tt.count, tt.opts, tt.unique)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GeneratePasswordBatch() expected error but got none")
+					return
+				}
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("GeneratePasswordBatch() error = %v, should contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GeneratePasswordBatch() unexpected error = %v", err)
+			}
+			if len(passwords) != tt.count {
+				t.Fatalf("GeneratePasswordBatch() returned %d passwords, want %d", len(passwords), tt.count)
+			}
+
+			charset, _ := buildCharset(tt.opts.UseUpper, tt.opts.UseLower, tt.opts.UseNumbers, tt.opts.UseSpecial)
+			seen := make(map[string]bool)
+			for _, password := range passwords {
+				if len(password) != tt.opts.Length {
+					t.Errorf("GeneratePasswordBatch() password %q length = %d, want %d", password, len(password), tt.opts.Length)
+				}
+				for _, char := range password {
+					if !strings.ContainsRune(charset, char) {
+						t.Errorf("GeneratePasswordBatch() password %q contains %q outside the charset", password, char)
+					}
+				}
+				if tt.unique && seen[password] {
+					t.Errorf("GeneratePasswordBatch() returned duplicate %q", password)
+				}
+				seen[password] = true
+			}
+		})
+	}
+}
+
+func TestByteSamplerUniform(t *testing.T) {
+	// 26 does not divide 256, so a plain modulo would favour the first letters
+	sampler := newByteSampler()
+	counts := make([]int, 26)
+	draws := 26000
+	for i := 0; i < draws; i++ {
+		idx, err := sampler.index(len(counts))
+		if err != nil {
+			t.Fatalf("index() unexpected error = %v", err)
+		}
+		counts[idx]++
+	}
+
+	for i, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Errorf("index %d drawn %d times, expected about 1000", i, c)
+		}
+	}
+}
+
+func TestStreamPasswords(t *testing.T) {
+	passwords, errc := StreamPasswords(context.Background(), 50, DefaultPasswordOptions(), true)
+
+	seen := make(map[string]bool)
+	for password := range passwords {
+		if len(password) != 30 {
+			t.Errorf("StreamPasswords() password length = %d, want 30", len(password))
+		}
+		if seen[password] {
+			t.Errorf("StreamPasswords() sent duplicate %q", password)
+		}
+		seen[password] = true
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("StreamPasswords() unexpected error = %v", err)
+	}
+	if len(seen) != 50 {
+		t.Errorf("StreamPasswords() sent %d passwords, want 50", len(seen))
+	}
+}
+
+func TestStreamPasswordsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	passwords, errc := StreamPasswords(ctx, 0, DefaultPasswordOptions(), false)
+
+	for i := 0; i < 10; i++ {
+		<-passwords
+	}
+	cancel()
+
+	// Drain until the generator notices the cancellation
+	for range passwords {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamPasswords() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamPasswordsInvalidOptions(t *testing.T) {
+	passwords, errc := StreamPasswords(context.Background(), 5, PasswordOptions{Length: 8}, false)
+	for range passwords {
+		t.Error("StreamPasswords() sent a password for invalid options")
+	}
+	if err := <-errc; err == nil {
+		t.Error("StreamPasswords() expected error for invalid options")
+	}
+}
+
+func BenchmarkGeneratePassword(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := GeneratePassword(30); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGeneratePasswordBatch(b *testing.B) {
+	passwords, err := GeneratePasswordBatch(b.N, DefaultPasswordOptions(), false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	_ = passwords
+}
@@ -0,0 +1,170 @@
+package password
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// GeneratedPassword is a generated password together with its strength
+type GeneratedPassword struct {
+	Password string
+	// EntropyBits is the entropy of the generation process, i.e. log2 of the
+	// number of equally likely passwords it can produce
+	EntropyBits float64
+	// CharsetSize is the number of distinct characters the password was drawn from
+	CharsetSize int
+}
+
+// InsufficientEntropyError is returned when the requested password shape
+// falls below the configured entropy floor. Nothing is generated in that case.
+type InsufficientEntropyError struct {
+	EntropyBits    float64
+	MinEntropyBits float64
+}
+
+func (e *InsufficientEntropyError) Error() string {
+	return fmt.Sprintf("password entropy %.1f bits is below the minimum of %.1f bits", e.EntropyBits, e.MinEntropyBits)
+}
+
+// PasswordEntropy returns the entropy in bits of a password of the given
+// length drawn uniformly from charsetSize characters
+func PasswordEntropy(length, charsetSize int) float64 {
+	if length <= 0 || charsetSize <= 1 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(charsetSize))
+}
+
+// checkEntropy returns an InsufficientEntropyError if bits is below the floor.
+// A floor of 0 disables the check.
+func checkEntropy(bits, minEntropyBits float64) error {
+	if minEntropyBits > 0 && bits < minEntropyBits {
+		return &InsufficientEntropyError{EntropyBits: bits, MinEntropyBits: minEntropyBits}
+	}
+	return nil
+}
+
+// coverageEntropy returns log2 of the number of passwords of the given
+// length over the disjoint sets that contain a character of every set, which
+// are exactly the passwords GeneratePasswordWithOptions picks uniformly from.
+// The count follows from inclusion-exclusion over the sets left out.
+func coverageEntropy(length int, sets []string) float64 {
+	if length <= 0 {
+		return 0
+	}
+	total := 0
+	for _, set := range sets {
+		total += len(set)
+	}
+	if length < len(sets) {
+		return PasswordEntropy(length, total)
+	}
+
+	count := new(big.Int)
+	term := new(big.Int)
+	for mask := 0; mask < 1<<len(sets); mask++ {
+		size := total
+		for i, set := range sets {
+			if mask&(1<<i) != 0 {
+				size -= len(set)
+			}
+		}
+		term.Exp(big.NewInt(int64(size)), big.NewInt(int64(length)), nil)
+		if bits.OnesCount(uint(mask))%2 == 0 {
+			count.Add(count, term)
+		} else {
+			count.Sub(count, term)
+		}
+	}
+
+	if count.Cmp(big.NewInt(1)) <= 0 {
+		return 0
+	}
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(count).MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
+
+// EntropyBits returns the entropy of passwords generated with these options
+func (opts PasswordOptions) EntropyBits() (float64, error) {
+	sets, err := selectedSets(opts.UseUpper, opts.UseLower, opts.UseNumbers, opts.UseSpecial)
+	if err != nil {
+		return 0, err
+	}
+	return coverageEntropy(opts.Length, sets), nil
+}
+
+// GeneratePasswordWithEntropy generates a password like
+// GeneratePasswordWithOptions and reports its entropy. If opts.MinEntropyBits
+// is set and the options cannot reach it an *InsufficientEntropyError is returned.
+func GeneratePasswordWithEntropy(opts PasswordOptions) (*GeneratedPassword, error) {
+	charset, err := buildCharset(opts.UseUpper, opts.UseLower, opts.UseNumbers, opts.UseSpecial)
+	if err != nil {
+		return nil, err
+	}
+	bits, _ := opts.EntropyBits()
+	if err := checkEntropy(bits, opts.MinEntropyBits); err != nil {
+		return nil, err
+	}
+
+	password, err := GeneratePasswordWithOptions(opts.Length, opts.UseUpper, opts.UseLower, opts.UseNumbers, opts.UseSpecial)
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedPassword{Password: password, EntropyBits: bits, CharsetSize: len(charset)}, nil
+}
+
+// GeneratePasswordDefaultWithEntropy generates a password like
+// GeneratePasswordDefault and reports its entropy (about 193.7 bits)
+func GeneratePasswordDefaultWithEntropy() (*GeneratedPassword, error) {
+	return GeneratePasswordWithEntropy(DefaultPasswordOptions())
+}
+
+// EntropyBits returns a conservative estimate of the entropy of passwords
+// generated for the rules: each required class only contributes the entropy
+// of one draw from that class, and the shuffle is ignored. With
+// max-consecutive, any position after the first MaxConsecutive may have to
+// skip the previous character, so those draws count one choice less.
+func (r *PasswordRules) EntropyBits() float64 {
+	length := r.length()
+	charsetSize := len(r.Charset())
+	free := length - len(r.Required)
+	if r.MaxConsecutive == 0 {
+		bits := PasswordEntropy(free, charsetSize)
+		for _, required := range r.Required {
+			bits += PasswordEntropy(1, len(required))
+		}
+		return bits
+	}
+
+	// Only the first MaxConsecutive positions never exclude a character, and
+	// the required classes may take all of them
+	full := min(max(r.MaxConsecutive-len(r.Required), 0), free)
+	bits := PasswordEntropy(full, charsetSize) + PasswordEntropy(free-full, charsetSize-1)
+	for _, required := range r.Required {
+		bits += PasswordEntropy(1, len(required)-1)
+	}
+	return bits
+}
+
+// GeneratePasswordForRulesWithEntropy generates a password like
+// GeneratePasswordForRules and reports its estimated entropy, refusing rules
+// whose estimate is below minEntropyBits (0 disables the check)
+func GeneratePasswordForRulesWithEntropy(rules *PasswordRules, minEntropyBits float64) (*GeneratedPassword, error) {
+	if rules == nil {
+		return nil, fmt.Errorf("%w: nil rules", ErrInvalidPasswordRules)
+	}
+	bits := rules.EntropyBits()
+	if err := checkEntropy(bits, minEntropyBits); err != nil {
+		return nil, err
+	}
+
+	password, err := GeneratePasswordForRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedPassword{Password: password, EntropyBits: bits, CharsetSize: len(rules.Charset())}, nil
+}
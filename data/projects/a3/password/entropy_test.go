@@ -0,0 +1,164 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPasswordEntropy(t *testing.T) {
+	tests := []struct {
+		name        string
+		length      int
+		charsetSize int
+		want        float64
+	}{
+		{name: "Six digits", length: 6, charsetSize: 10, want: 19.93},
+		{name: "Default password", length: 30, charsetSize: 88, want: 193.78},
+		{name: "Single character set member", length: 10, charsetSize: 1, want: 0},
+		{name: "Zero length", length: 0, charsetSize: 88, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PasswordEntropy(tt.length, tt.charsetSize)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("PasswordEntropy(%d, %d) = %.2f, want %.2f", tt.length, tt.charsetSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoverageEntropy(t *testing.T) {
+	all := []string{uppercaseLetters, lowercaseLetters, numbers, specialChars}
+	tests := []struct {
+		name   string
+		length int
+		sets   []string
+		want   float64
+	}{
+		// One character per set: 4! orderings of 26*26*10*26 choices
+		{name: "One character per set", length: 4, sets: all, want: math.Log2(24 * 26 * 26 * 10 * 26)},
+		{name: "Shorter than the number of sets", length: 2, sets: all, want: 2 * math.Log2(88)},
+		{name: "Single set", length: 6, sets: []string{numbers}, want: 6 * math.Log2(10)},
+		{name: "Two sets", length: 2, sets: []string{numbers, uppercaseLetters}, want: math.Log2(2 * 10 * 26)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coverageEntropy(tt.length, tt.sets); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("coverageEntropy(%d) = %.6f, want %.6f", tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePasswordWithEntropy(t *testing.T) {
+	result, err := GeneratePasswordDefaultWithEntropy()
+	if err != nil {
+		t.Fatalf("GeneratePasswordDefaultWithEntropy() unexpected error = %v", err)
+	}
+	if len(result.Password) != 30 {
+		t.Errorf("GeneratePasswordDefaultWithEntropy() length = %d, want 30", len(result.Password))
+	}
+	if result.CharsetSize != 88 {
+		t.Errorf("GeneratePasswordDefaultWithEntropy() charset size = %d, want 88", result.CharsetSize)
+	}
+	// Slightly below 30*log2(88) = 193.78 because passwords missing a
+	// character set are never produced
+	if math.Abs(result.EntropyBits-193.74) > 0.01 {
+		t.Errorf("GeneratePasswordDefaultWithEntropy() entropy = %.2f, want 193.74", result.EntropyBits)
+	}
+
+	// Six digits is only ~20 bits and must be refused with a 64 bit floor
+	weak := PasswordOptions{Length: 6, UseNumbers: true, MinEntropyBits: 64}
+	_, err = GeneratePasswordWithEntropy(weak)
+	var entropyErr *InsufficientEntropyError
+	if !errors.As(err, &entropyErr) {
+		t.Fatalf("GeneratePasswordWithEntropy() error = %v, want *InsufficientEntropyError", err)
+	}
+	if entropyErr.MinEntropyBits != 64 || math.Abs(entropyErr.EntropyBits-19.93) > 0.01 {
+		t.Errorf("InsufficientEntropyError = %+v, unexpected values", entropyErr)
+	}
+
+	weak.MinEntropyBits = 0
+	result, err = GeneratePasswordWithEntropy(weak)
+	if err != nil {
+		t.Fatalf("GeneratePasswordWithEntropy() without floor unexpected error = %v", err)
+	}
+	if result.CharsetSize != 10 || len(result.Password) != 6 {
+		t.Errorf("GeneratePasswordWithEntropy() = %+v, want 6 digits", result)
+	}
+
+	if _, err := GeneratePasswordWithEntropy(PasswordOptions{Length: 8}); err == nil {
+		t.Error("GeneratePasswordWithEntropy() expected error with no character sets")
+	}
+}
+
+func TestBatchEntropyFloor(t *testing.T) {
+	opts := PasswordOptions{Length: 8, UseLower: true, MinEntropyBits: 80}
+
+	_, err := GeneratePasswordBatch(10, opts, false)
+	var entropyErr *InsufficientEntropyError
+	if !errors.As(err, &entropyErr) {
+		t.Errorf("GeneratePasswordBatch() error = %v, want *InsufficientEntropyError", err)
+	}
+
+	opts.Length = 20
+	if _, err := GeneratePasswordBatch(10, opts, false); err != nil {
+		t.Errorf("GeneratePasswordBatch() unexpected error = %v", err)
+	}
+}
+
+func TestRulesEntropy(t *testing.T) {
+	rules, err := ParsePasswordRules("minlength: 8; maxlength: 8; required: digit; allowed: lower")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() unexpected error = %v", err)
+	}
+
+	// One digit (log2 10) plus seven characters from 36
+	want := math.Log2(10) + 7*math.Log2(36)
+	if got := rules.EntropyBits(); math.Abs(got-want) > 0.001 {
+		t.Errorf("EntropyBits() = %.3f, want %.3f", got, want)
+	}
+
+	result, err := GeneratePasswordForRulesWithEntropy(rules, 30)
+	if err != nil {
+		t.Fatalf("GeneratePasswordForRulesWithEntropy() unexpected error = %v", err)
+	}
+	if err := Validate(result.Password, rules); err != nil {
+		t.Errorf("GeneratePasswordForRulesWithEntropy() password fails Validate: %v", err)
+	}
+	if result.CharsetSize != 36 {
+		t.Errorf("GeneratePasswordForRulesWithEntropy() charset size = %d, want 36", result.CharsetSize)
+	}
+
+	_, err = GeneratePasswordForRulesWithEntropy(rules, 128)
+	var entropyErr *InsufficientEntropyError
+	if !errors.As(err, &entropyErr) {
+		t.Errorf("GeneratePasswordForRulesWithEntropy() error = %v, want *InsufficientEntropyError", err)
+	}
+}
+
+func TestRulesEntropyMaxConsecutive(t *testing.T) {
+	// Only abab... and baba... are possible, one bit however long
+	rules, err := ParsePasswordRules("allowed: [ab]; max-consecutive: 1")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() unexpected error = %v", err)
+	}
+	if got := rules.EntropyBits(); math.Abs(got-1) > 0.001 {
+		t.Errorf("EntropyBits() = %.3f, want 1", got)
+	}
+	_, err = GeneratePasswordForRulesWithEntropy(rules, 20)
+	var entropyErr *InsufficientEntropyError
+	if !errors.As(err, &entropyErr) {
+		t.Errorf("GeneratePasswordForRulesWithEntropy() error = %v, want *InsufficientEntropyError", err)
+	}
+
+	// The first two draws are free, the other six may have to skip one digit
+	rules, _ = ParsePasswordRules("minlength: 8; maxlength: 8; allowed: digit; max-consecutive: 2")
+	want := 2*math.Log2(10) + 6*math.Log2(9)
+	if got := rules.EntropyBits(); math.Abs(got-want) > 0.001 {
+		t.Errorf("EntropyBits() = %.3f, want %.3f", got, want)
+	}
+}
@@ -0,0 +1,326 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/hashpassword/internal/cryptorand"
+)
+
+// asciiPrintableChars is every printable ASCII character, including space
+const asciiPrintableChars = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// DefaultRulesLength is the length used for rule-based generation when the
+// rules allow it
+const DefaultRulesLength = 30
+
+var (
+	// ErrInvalidPasswordRules is returned when a rules descriptor cannot be parsed
+	ErrInvalidPasswordRules = errors.New("invalid password rules")
+	// ErrPasswordRulesViolation is returned when a password does not satisfy the rules
+	ErrPasswordRulesViolation = errors.New("password does not satisfy rules")
+)
+
+// PasswordRules is a parsed password rules descriptor in the syntax of
+// Apple's passwordrules attribute, for example:
+//
+//	minlength: 12; maxlength: 32; required: upper; required: digit; allowed: [-_]; max-consecutive: 2
+//
+// Each entry of Required is a character set from which at least one character
+// must appear. Allowed lists the additional characters that may be used.
+type PasswordRules struct {
+	MinLength      int
+	MaxLength      int
+	Required       []string
+	Allowed        string
+	MaxConsecutive int
+}
+
+// ParsePasswordRules parses a passwordrules descriptor.
+// Supported character classes are upper, lower, digit, special (the package's
+// special character set), ascii-printable and custom classes such as [-_].
+func ParsePasswordRules(descriptor string) (*PasswordRules, error) {
+	rules := &PasswordRules{}
+	var allowed []string
+
+	for _, property := range strings.Split(descriptor, ";") {
+		property = strings.TrimSpace(property)
+		if property == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(property, ":")
+		if !found {
+			return nil, fmt.Errorf("%w: missing ':' in %q", ErrInvalidPasswordRules, property)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "minlength", "maxlength", "max-consecutive":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%w: %s must be a positive integer, got %q", ErrInvalidPasswordRules, name, value)
+			}
+			switch name {
+			case "minlength":
+				rules.MinLength = n
+			case "maxlength":
+				rules.MaxLength = n
+			default:
+				rules.MaxConsecutive = n
+			}
+		case "required", "allowed":
+			chars, err := parseCharacterClasses(value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "required" {
+				rules.Required = append(rules.Required, chars)
+			} else {
+				allowed = append(allowed, chars)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown property %q", ErrInvalidPasswordRules, name)
+		}
+	}
+
+	rules.Allowed = uniqueChars(strings.Join(allowed, ""))
+
+	if err := rules.check(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// parseCharacterClasses parses a comma separated list of character classes
+// and returns the union of their characters
+func parseCharacterClasses(value string) (string, error) {
+	var chars strings.Builder
+	for value != "" {
+		value = strings.TrimLeft(value, " ,")
+		if value == "" {
+			break
+		}
+
+		if value[0] == '[' {
+			end := strings.IndexByte(value[1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("%w: unterminated custom class %q", ErrInvalidPasswordRules, value)
+			}
+			custom := value[1 : end+1]
+			for i := 0; i < len(custom); i++ {
+				if strings.IndexByte(asciiPrintableChars, custom[i]) < 0 {
+					return "", fmt.Errorf("%w: unsupported character %q in custom class", ErrInvalidPasswordRules, custom[i])
+				}
+			}
+			chars.WriteString(custom)
+			value = value[end+2:]
+			continue
+		}
+
+		identifier := value
+		if i := strings.IndexByte(value, ','); i >= 0 {
+			identifier, value = value[:i], value[i+1:]
+		} else {
+			value = ""
+		}
+
+		switch strings.ToLower(strings.TrimSpace(identifier)) {
+		case "upper":
+			chars.WriteString(uppercaseLetters)
+		case "lower":
+			chars.WriteString(lowercaseLetters)
+		case "digit":
+			chars.WriteString(numbers)
+		case "special":
+			chars.WriteString(specialChars)
+		case "ascii-printable":
+			chars.WriteString(asciiPrintableChars)
+		default:
+			return "", fmt.Errorf("%w: unsupported character class %q", ErrInvalidPasswordRules, identifier)
+		}
+	}
+
+	if chars.Len() == 0 {
+		return "", fmt.Errorf("%w: empty character class", ErrInvalidPasswordRules)
+	}
+	return uniqueChars(chars.String()), nil
+}
+
+// uniqueChars returns the distinct characters of s in sorted order
+func uniqueChars(s string) string {
+	seen := make(map[byte]bool, len(s))
+	result := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if !seen[s[i]] {
+			seen[s[i]] = true
+			result = append(result, s[i])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return string(result)
+}
+
+// check reports whether the rules are internally consistent
+func (r *PasswordRules) check() error {
+	if r.MinLength < 0 || r.MaxLength < 0 || r.MaxConsecutive < 0 {
+		return fmt.Errorf("%w: negative limits", ErrInvalidPasswordRules)
+	}
+	if r.MaxLength > 0 && r.MinLength > r.MaxLength {
+		return fmt.Errorf("%w: minlength %d exceeds maxlength %d", ErrInvalidPasswordRules, r.MinLength, r.MaxLength)
+	}
+	if r.MaxLength > 0 && len(r.Required) > r.MaxLength {
+		return fmt.Errorf("%w: %d required classes do not fit in maxlength %d", ErrInvalidPasswordRules, len(r.Required), r.MaxLength)
+	}
+	for _, required := range r.Required {
+		if required == "" {
+			return fmt.Errorf("%w: empty required class", ErrInvalidPasswordRules)
+		}
+	}
+	if r.MaxConsecutive > 0 && len(r.Charset()) == 1 && r.length() > r.MaxConsecutive {
+		return fmt.Errorf("%w: a single allowed character cannot satisfy max-consecutive %d", ErrInvalidPasswordRules, r.MaxConsecutive)
+	}
+	return nil
+}
+
+// Charset returns every character a compliant password may contain.
+// Without any required or allowed classes all printable ASCII is allowed.
+func (r *PasswordRules) Charset() string {
+	charset := r.Allowed + strings.Join(r.Required, "")
+	if charset == "" {
+		return asciiPrintableChars
+	}
+	return uniqueChars(charset)
+}
+
+// length picks the generated password length: DefaultRulesLength clamped to
+// the rules' bounds
+func (r *PasswordRules) length() int {
+	length := DefaultRulesLength
+	if length < r.MinLength {
+		length = r.MinLength
+	}
+	if r.MaxLength > 0 && length > r.MaxLength {
+		length = r.MaxLength
+	}
+	if length < len(r.Required) {
+		length = len(r.Required)
+	}
+	return length
+}
+
+// GeneratePasswordForRules generates a random password that satisfies the
+// rules. Each required class gets a random position and the other positions
+// draw from the full allowed charset. Characters are drawn left to right,
+// excluding the previous character once it has repeated max-consecutive
+// times; a required class that is only that character is already present and
+// its position draws from the charset instead.
+func GeneratePasswordForRules(rules *PasswordRules) (string, error) {
+	if rules == nil {
+		return "", fmt.Errorf("%w: nil rules", ErrInvalidPasswordRules)
+	}
+	if err := rules.check(); err != nil {
+		return "", err
+	}
+
+	charset := rules.Charset()
+	length := rules.length()
+
+	// classes[i] is the required class drawn at position i, "" for none.
+	// The first positions of a Fisher-Yates shuffle are the required ones.
+	positions := make([]int, length)
+	for i := range positions {
+		positions[i] = i
+	}
+	classes := make([]string, length)
+	for k, required := range rules.Required {
+		j, err := cryptorand.Index(length - k)
+		if err != nil {
+			return "", err
+		}
+		positions[k], positions[k+j] = positions[k+j], positions[k]
+		classes[positions[k]] = required
+	}
+
+	password := make([]byte, 0, length)
+	run := 0
+	for i := 0; i < length; i++ {
+		exclude := -1
+		if rules.MaxConsecutive > 0 && run >= rules.MaxConsecutive {
+			exclude = int(password[i-1])
+		}
+		set := without(classes[i], exclude)
+		if set == "" {
+			set = without(charset, exclude)
+		}
+		j, err := cryptorand.Index(len(set))
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 && set[j] == password[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		password = append(password, set[j])
+	}
+	return string(password), nil
+}
+
+// without returns set without the character c, or set itself when c is -1
+func without(set string, c int) string {
+	if c < 0 {
+		return set
+	}
+	return strings.ReplaceAll(set, string([]byte{byte(c)}), "")
+}
+
+// Validate checks a password against the rules.
+// Returns an error wrapping ErrPasswordRulesViolation describing the first
+// rule that is not satisfied.
+func Validate(password string, rules *PasswordRules) error {
+	if rules == nil {
+		return fmt.Errorf("%w: nil rules", ErrInvalidPasswordRules)
+	}
+
+	if len(password) < rules.MinLength {
+		return fmt.Errorf("%w: length %d is below minlength %d", ErrPasswordRulesViolation, len(password), rules.MinLength)
+	}
+	if rules.MaxLength > 0 && len(password) > rules.MaxLength {
+		return fmt.Errorf("%w: length %d exceeds maxlength %d", ErrPasswordRulesViolation, len(password), rules.MaxLength)
+	}
+
+	charset := rules.Charset()
+	for i := 0; i < len(password); i++ {
+		if strings.IndexByte(charset, password[i]) < 0 {
+			return fmt.Errorf("%w: character %q is not allowed", ErrPasswordRulesViolation, password[i])
+		}
+	}
+
+	for _, required := range rules.Required {
+		if !strings.ContainsAny(password, required) {
+			return fmt.Errorf("%w: missing a required character from %q", ErrPasswordRulesViolation, required)
+		}
+	}
+
+	if rules.MaxConsecutive > 0 {
+		run := 0
+		for i := 0; i < len(password); i++ {
+			if i > 0 && password[i] == password[i-1] {
+				run++
+			} else {
+				run = 1
+			}
+			if run > rules.MaxConsecutive {
+				return fmt.Errorf("%w: more than %d consecutive %q", ErrPasswordRulesViolation, rules.MaxConsecutive, password[i])
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePasswordRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		descriptor  string
+		wantErr     bool
+		errContains string
+		check       func(*PasswordRules) bool
+	}{
+		{
+			name:       "Apple style descriptor",
+			descriptor: "minlength: 12; required: upper; required: digit; allowed: [-_]; max-consecutive: 2",
+			check: func(r *PasswordRules) bool {
+				return r.MinLength == 12 && r.MaxConsecutive == 2 &&
+					len(r.Required) == 2 && r.Required[0] == uppercaseLetters &&
+					r.Required[1] == numbers && r.Allowed == "-_"
+			},
+		},
+		{
+			name:       "Required union of classes",
+			descriptor: "required: upper, lower; maxlength: 20",
+			check: func(r *PasswordRules) bool {
+				return len(r.Required) == 1 && r.Required[0] == uniqueChars(uppercaseLetters+lowercaseLetters) && r.MaxLength == 20
+			},
+		},
+		{
+			name:       "Case insensitive names and trailing semicolon",
+			descriptor: "MinLength: 8; Allowed: lower, [!];",
+			check: func(r *PasswordRules) bool {
+				return r.MinLength == 8 && r.Allowed == uniqueChars(lowercaseLetters+"!")
+			},
+		},
+		{
+			name:       "Empty descriptor",
+			descriptor: "",
+			check: func(r *PasswordRules) bool {
+				return r.Charset() == asciiPrintableChars
+			},
+		},
+		{
+			name:        "Unknown property",
+			descriptor:  "minlength: 8; colour: blue",
+			wantErr:     true,
+			errContains: "unknown property",
+		},
+		{
+			name:        "Unknown class",
+			descriptor:  "required: unicode",
+			wantErr:     true,
+			errContains: "unsupported character class",
+		},
+		{
+			name:        "Unterminated custom class",
+			descriptor:  "allowed: [-_",
+			wantErr:     true,
+			errContains: "unterminated",
+		},
+		{
+			name:        "Non numeric length",
+			descriptor:  "minlength: twelve",
+			wantErr:     true,
+			errContains: "positive integer",
+		},
+		{
+			name:        "Min above max",
+			descriptor:  "minlength: 20; maxlength: 10",
+			wantErr:     true,
+			errContains: "exceeds maxlength",
+		},
+		{
+			name:        "Missing colon",
+			descriptor:  "minlength 8",
+			wantErr:     true,
+			errContains: "missing ':'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParsePasswordRules(tt.descriptor)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePasswordRules() expected error but got none")
+					return
+				}
+				if !errors.Is(err, ErrInvalidPasswordRules) {
+					t.Errorf("ParsePasswordRules() error = %v, want ErrInvalidPasswordRules", err)
+				}
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ParsePasswordRules() error = %v, should contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParsePasswordRules() unexpected error = %v", err)
+				return
+			}
+			if tt.check != nil && !tt.check(rules) {
+				t.Errorf("ParsePasswordRules() = %+v does not match expectations", rules)
+			}
+		})
+	}
+}
+
+func TestGeneratePasswordForRules(t *testing.T) {
+	descriptors := []string{
+		"minlength: 12; required: upper; required: digit; allowed: [-_]; max-consecutive: 2",
+		"minlength: 8; maxlength: 8; required: lower; required: special",
+		"maxlength: 4; required: [ab]; required: [cd]; required: [ef]; required: [gh]",
+		"required: digit; max-consecutive: 1",
+		"minlength: 40; allowed: ascii-printable",
+		// Only alternating passwords comply; drawing whole candidates and
+		// rejecting them practically never finds one
+		"allowed: [ab]; max-consecutive: 1",
+		"required: [ab]; minlength: 20; maxlength: 20; max-consecutive: 1",
+		"required: [a]; required: [ab]; allowed: [a]; max-consecutive: 1",
+	}
+
+	for _, descriptor := range descriptors {
+		t.Run(descriptor, func(t *testing.T) {
+			rules, err := ParsePasswordRules(descriptor)
+			if err != nil {
+				t.Fatalf("ParsePasswordRules() unexpected error = %v", err)
+			}
+
+			for i := 0; i < 50; i++ {
+				password, err := GeneratePasswordForRules(rules)
+				if err != nil {
+					t.Fatalf("GeneratePasswordForRules() unexpected error = %v", err)
+				}
+				if err := Validate(password, rules); err != nil {
+					t.Fatalf("GeneratePasswordForRules() produced %q which fails Validate: %v", password, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePasswordForRulesLength(t *testing.T) {
+	rules, _ := ParsePasswordRules("maxlength: 16")
+	password, err := GeneratePasswordForRules(rules)
+	if err != nil {
+		t.Fatalf("GeneratePasswordForRules() unexpected error = %v", err)
+	}
+	if len(password) != 16 {
+		t.Errorf("GeneratePasswordForRules() length = %d, want 16", len(password))
+	}
+
+	rules, _ = ParsePasswordRules("minlength: 64")
+	password, err = GeneratePasswordForRules(rules)
+	if err != nil {
+		t.Fatalf("GeneratePasswordForRules() unexpected error = %v", err)
+	}
+	if len(password) != 64 {
+		t.Errorf("GeneratePasswordForRules() length = %d, want 64", len(password))
+	}
+}
+
+func TestGeneratePasswordForRulesImpossible(t *testing.T) {
+	if _, err := ParsePasswordRules("minlength: 4; allowed: [a]; max-consecutive: 1"); !errors.Is(err, ErrInvalidPasswordRules) {
+		t.Errorf("ParsePasswordRules() error = %v, want ErrInvalidPasswordRules for unsatisfiable rules", err)
+	}
+	rules := &PasswordRules{MinLength: 4, Allowed: "a", MaxConsecutive: 1}
+	if _, err := GeneratePasswordForRules(rules); !errors.Is(err, ErrInvalidPasswordRules) {
+		t.Errorf("GeneratePasswordForRules() error = %v, want ErrInvalidPasswordRules for unsatisfiable rules", err)
+	}
+
+	if _, err := GeneratePasswordForRules(nil); err == nil {
+		t.Error("GeneratePasswordForRules(nil) expected error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rules, err := ParsePasswordRules("minlength: 8; maxlength: 12; required: upper; required: digit; allowed: lower; max-consecutive: 2")
+	if err != nil {
+		t.Fatalf("ParsePasswordRules() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		errContains string
+	}{
+		{name: "Compliant", password: "Abcdefg1"},
+		{name: "Too short", password: "Abc1", errContains: "below minlength"},
+		{name: "Too long", password: "Abcdefghijk12", errContains: "exceeds maxlength"},
+		{name: "Missing upper", password: "abcdefg1", errContains: "missing a required"},
+		{name: "Missing digit", password: "Abcdefgh", errContains: "missing a required"},
+		{name: "Disallowed character", password: "Abcdefg1!", errContains: "not allowed"},
+		{name: "Too many consecutive", password: "Abcddd12", errContains: "consecutive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.password, rules)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Validate(%q) unexpected error = %v", tt.password, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPasswordRulesViolation) {
+				t.Errorf("Validate(%q) error = %v, want ErrPasswordRulesViolation", tt.password, err)
+				return
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Validate(%q) error = %v, should contain %v", tt.password, err, tt.errContains)
+			}
+		})
+	}
+}
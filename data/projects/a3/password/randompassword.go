@@ -0,0 +1,118 @@
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// Character sets for password generation
+const (
+	uppercaseLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	lowercaseLetters = "abcdefghijklmnopqrstuvwxyz"
+	numbers          = "0123456789"
+	specialChars     = "!@#$%^&*()_+-=[]{}|;:,.<>?"
+)
+
+// maxCoverageAttempts bounds how many candidates are drawn while looking for
+// one that contains every selected character set. The worst case, one
+// character per set, succeeds about 7% of the time per attempt.
+const maxCoverageAttempts = 1000
+
+// GeneratePassword generates a random password of the specified length
+// using all character sets (upper, lower, numbers, special)
+func GeneratePassword(length int) (string, error) {
+	return GeneratePasswordWithOptions(length, true, true, true, true)
+}
+
+// GeneratePasswordDefault generates a 30-character random password
+// using all character sets
+func GeneratePasswordDefault() (string, error) {
+	return GeneratePassword(30)
+}
+
+// GeneratePasswordWithOptions generates a random password with customizable character sets.
+// When length allows it the password contains at least one character of every
+// selected set; candidates missing a set are redrawn so every qualifying
+// password stays equally likely.
+func GeneratePasswordWithOptions(length int, useUpper, useLower, useNumbers, useSpecial bool) (string, error) {
+	if length <= 0 {
+		return "", errors.New("password length must be greater than 0")
+	}
+
+	// Build the character set based on options
+	sets, err := selectedSets(useUpper, useLower, useNumbers, useSpecial)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate password using crypto/rand for secure random generation.
+	// Index by rune rather than byte so multi-byte characters stay intact.
+	chars := []rune(strings.Join(sets, ""))
+	charsetLen := big.NewInt(int64(len(chars)))
+
+	for attempt := 0; attempt < maxCoverageAttempts; attempt++ {
+		password := make([]rune, length)
+		for i := 0; i < length; i++ {
+			// Get a random index into the charset
+			randomIndex, err := rand.Int(rand.Reader, charsetLen)
+			if err != nil {
+				return "", err
+			}
+			password[i] = chars[randomIndex.Int64()]
+		}
+
+		if coversSets(string(password), sets) {
+			return string(password), nil
+		}
+	}
+
+	return "", errors.New("failed to generate a password containing every selected character set")
+}
+
+// selectedSets returns the selected character sets in a fixed order
+func selectedSets(useUpper, useLower, useNumbers, useSpecial bool) ([]string, error) {
+	var sets []string
+	if useUpper {
+		sets = append(sets, uppercaseLetters)
+	}
+	if useLower {
+		sets = append(sets, lowercaseLetters)
+	}
+	if useNumbers {
+		sets = append(sets, numbers)
+	}
+	if useSpecial {
+		sets = append(sets, specialChars)
+	}
+
+	if len(sets) == 0 {
+		return nil, errors.New("at least one character set must be selected")
+	}
+	return sets, nil
+}
+
+// buildCharset concatenates the selected character sets
+func buildCharset(useUpper, useLower, useNumbers, useSpecial bool) (string, error) {
+	sets, err := selectedSets(useUpper, useLower, useNumbers, useSpecial)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(sets, ""), nil
+}
+
+// coversSets reports whether password contains a character of every set.
+// Passwords shorter than the number of sets cannot, and always qualify.
+func coversSets(password string, sets []string) bool {
+	if utf8.RuneCountInString(password) < len(sets) {
+		return true
+	}
+	for _, set := range sets {
+		if !strings.ContainsAny(password, set) {
+			return false
+		}
+	}
+	return true
+}
@@ -70,6 +70,11 @@ func TestGeneratePassword(t *testing.T) {
 				t.Errorf("GeneratePassword() length = %v, want %v", len(password), tt.length)
 			}
 
+			// A password shorter than the number of character sets cannot contain all of them
+			if tt.length < 4 {
+				return
+			}
+
 			// Verify password contains characters from expected sets
 			hasUpper := false
 			hasLower := false
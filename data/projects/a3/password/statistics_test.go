@@ -0,0 +1,117 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/example/hashpassword/randtest"
+)
+
+func TestGeneratePasswordWithOptionsStatistics(t *testing.T) {
+	// A single set has no coverage rejection, so every position and the
+	// whole sequence must be uniform over the set
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(2000, func() ([]string, error) {
+			p, err := GeneratePasswordWithOptions(8, false, true, false, false)
+			return randtest.Runes(p), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		symbols := randtest.Runes(lowercaseLetters)
+		results, err := randtest.PositionTests("lowercase", samples, symbols)
+		if err != nil {
+			return nil, err
+		}
+		sequence, err := randtest.SequenceTests("lowercase", samples, symbols)
+		if err != nil {
+			return nil, err
+		}
+		return append(results, sequence...), nil
+	})
+}
+
+func TestGeneratePasswordCoverageStatistics(t *testing.T) {
+	// Coverage rejection shifts probability between sets, but characters of
+	// the same set must stay equally likely
+	sets := []string{uppercaseLetters, lowercaseLetters, numbers, specialChars}
+
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(1000, func() ([]string, error) {
+			p, err := GeneratePassword(12)
+			return randtest.Runes(p), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return withinSetTests("GeneratePassword", samples, sets)
+	})
+}
+
+func TestGeneratePasswordBatchStatistics(t *testing.T) {
+	opts := PasswordOptions{Length: 8, UseNumbers: true}
+
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		passwords, err := GeneratePasswordBatch(2000, opts, false)
+		if err != nil {
+			return nil, err
+		}
+		samples := make([][]string, len(passwords))
+		for i, p := range passwords {
+			samples[i] = randtest.Runes(p)
+		}
+		symbols := randtest.Runes(numbers)
+		results, err := randtest.PositionTests("batch", samples, symbols)
+		if err != nil {
+			return nil, err
+		}
+		sequence, err := randtest.SequenceTests("batch", samples, symbols)
+		if err != nil {
+			return nil, err
+		}
+		return append(results, sequence...), nil
+	})
+}
+
+func TestGenerateFromAlphabetStatistics(t *testing.T) {
+	alphabet := []string{"ก", "ข", "ค", "กิ", "👍🏽", "🇹🇭"}
+
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(2000, func() ([]string, error) {
+			p, err := GenerateFromAlphabet(6, alphabet)
+			return SplitSymbols(p), err
+		})
+		if err != nil {
+			return nil, err
+		}
+		results, err := randtest.PositionTests("alphabet", samples, alphabet)
+		if err != nil {
+			return nil, err
+		}
+		sequence, err := randtest.SequenceTests("alphabet", samples, alphabet)
+		if err != nil {
+			return nil, err
+		}
+		return append(results, sequence...), nil
+	})
+}
+
+// withinSetTests runs a uniformity test over the characters of each set
+func withinSetTests(name string, samples [][]string, sets []string) ([]randtest.Result, error) {
+	var all []string
+	for _, set := range sets {
+		all = append(all, randtest.Runes(set)...)
+	}
+	counts, err := randtest.Counts(samples, all)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]randtest.Result, 0, len(sets))
+	offset := 0
+	for _, set := range sets {
+		n := len(randtest.Runes(set))
+		results = append(results, randtest.UniformityTest(name+" "+set[:1]+"..", counts[offset:offset+n]))
+		offset += n
+	}
+	return results, nil
+}
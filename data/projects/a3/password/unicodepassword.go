@@ -0,0 +1,134 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/example/hashpassword/internal/cryptorand"
+)
+
+const (
+	// zeroWidthJoiner glues emoji into a single sequence, e.g. family emoji
+	zeroWidthJoiner = '\u200d'
+	// combiningKeycap turns a digit into a keycap emoji
+	combiningKeycap = '\u20e3'
+)
+
+// ErrInvalidAlphabet is returned when a custom alphabet is empty, not valid
+// UTF-8 or has an entry that is not one self-contained symbol
+var ErrInvalidAlphabet = errors.New("invalid alphabet")
+
+// SplitSymbols splits s into user-perceived characters: combining marks
+// (e.g. Thai vowel and tone marks), variation selectors, emoji skin tone
+// modifiers, zero width joiner sequences and regional indicator pairs (flags)
+// stay attached to their base character. This covers the common grapheme
+// cluster cases without a full Unicode segmentation table.
+func SplitSymbols(s string) []string {
+	var symbols []string
+	joinNext := false
+
+	for _, r := range s {
+		if len(symbols) > 0 {
+			last := len(symbols) - 1
+			if joinNext || extendsSymbol(r) || completesFlag(symbols[last], r) {
+				symbols[last] += string(r)
+				joinNext = r == zeroWidthJoiner
+				continue
+			}
+		}
+		symbols = append(symbols, string(r))
+		joinNext = false
+	}
+	return symbols
+}
+
+// extendsSymbol reports whether r attaches to the preceding character
+func extendsSymbol(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector) ||
+		r == zeroWidthJoiner || r == combiningKeycap ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) // emoji skin tone modifiers
+}
+
+// completesFlag reports whether r is the second regional indicator of a flag
+func completesFlag(symbol string, r rune) bool {
+	if !isRegionalIndicator(r) {
+		return false
+	}
+	first, size := utf8.DecodeRuneInString(symbol)
+	return size == len(symbol) && isRegionalIndicator(first)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isSelfContained reports whether symbol is exactly one symbol of
+// SplitSymbols that cannot merge with a neighbour: it does not start with a
+// combining character, end with a zero width joiner or consist of a single
+// regional indicator
+func isSelfContained(symbol string) bool {
+	if len(SplitSymbols(symbol)) != 1 {
+		return false
+	}
+	first, size := utf8.DecodeRuneInString(symbol)
+	last, _ := utf8.DecodeLastRuneInString(symbol)
+	return !extendsSymbol(first) && last != zeroWidthJoiner &&
+		!(size == len(symbol) && isRegionalIndicator(first))
+}
+
+// normalizeAlphabet validates the symbols and removes duplicates, which
+// would otherwise make some symbols more likely than others. Every entry
+// must be one self-contained symbol, or the generated password would hold
+// fewer or different symbols than requested.
+func normalizeAlphabet(alphabet []string) ([]string, error) {
+	seen := make(map[string]bool, len(alphabet))
+	symbols := make([]string, 0, len(alphabet))
+	for _, symbol := range alphabet {
+		if symbol == "" || !utf8.ValidString(symbol) || !isSelfContained(symbol) {
+			return nil, ErrInvalidAlphabet
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return nil, ErrInvalidAlphabet
+	}
+	return symbols, nil
+}
+
+// GenerateFromAlphabet generates a random string of length symbols drawn
+// uniformly from alphabet. Each symbol may be several runes, e.g. a Thai
+// consonant with a vowel mark or an emoji sequence, and is never split.
+// Duplicate symbols are ignored.
+func GenerateFromAlphabet(length int, alphabet []string) (string, error) {
+	if length <= 0 {
+		return "", errors.New("password length must be greater than 0")
+	}
+	symbols, err := normalizeAlphabet(alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	var password strings.Builder
+	for i := 0; i < length; i++ {
+		idx, err := cryptorand.Index(len(symbols))
+		if err != nil {
+			return "", err
+		}
+		password.WriteString(symbols[idx])
+	}
+	return password.String(), nil
+}
+
+// GenerateFromAlphabetString is GenerateFromAlphabet with the alphabet given
+// as a string, split into symbols with SplitSymbols
+func GenerateFromAlphabetString(length int, alphabet string) (string, error) {
+	if !utf8.ValidString(alphabet) {
+		return "", ErrInvalidAlphabet
+	}
+	return GenerateFromAlphabet(length, SplitSymbols(alphabet))
+}
@@ -0,0 +1,151 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	// thaiConsonants is a sample of Thai consonants (single runes)
+	thaiConsonants = "กขคงจฉชซญดตถทนบปผพฟมยรลวสหอฮ"
+	// cyrillicLetters is the Russian lowercase alphabet
+	cyrillicLetters = "абвгдеёжзийклмнопрстуфхцчшщъыьэюя"
+)
+
+func TestSplitSymbols(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "ASCII", input: "abc", want: []string{"a", "b", "c"}},
+		{name: "Cyrillic", input: "жёя", want: []string{"ж", "ё", "я"}},
+		{name: "Thai with vowel and tone marks", input: "กิ่ขค", want: []string{"กิ่", "ข", "ค"}},
+		{name: "Combining accent", input: "éa", want: []string{"é", "a"}},
+		{name: "Skin tone modifier", input: "👍🏽👍", want: []string{"👍🏽", "👍"}},
+		{name: "ZWJ family", input: "👨‍👩‍👧x", want: []string{"👨‍👩‍👧", "x"}},
+		{name: "Flags", input: "🇹🇭🇯🇵", want: []string{"🇹🇭", "🇯🇵"}},
+		{name: "Keycap", input: "1️⃣#", want: []string{"1️⃣", "#"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitSymbols(tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("SplitSymbols(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFromAlphabetString(t *testing.T) {
+	alphabets := map[string]string{
+		"Thai":     thaiConsonants,
+		"Cyrillic": cyrillicLetters,
+		"Emoji":    "🐶🐱🐭🐹🐰🦊👍🏽🇹🇭",
+	}
+
+	for name, alphabet := range alphabets {
+		t.Run(name, func(t *testing.T) {
+			symbols := SplitSymbols(alphabet)
+			for i := 0; i < 50; i++ {
+				password, err := GenerateFromAlphabetString(20, alphabet)
+				if err != nil {
+					t.Fatalf("GenerateFromAlphabetString() unexpected error = %v", err)
+				}
+				if !utf8.ValidString(password) {
+					t.Fatalf("GenerateFromAlphabetString() = %q is not valid UTF-8", password)
+				}
+
+				got := SplitSymbols(password)
+				if len(got) != 20 {
+					t.Fatalf("GenerateFromAlphabetString() = %q has %d symbols, want 20", password, len(got))
+				}
+				for _, symbol := range got {
+					if !containsSymbol(symbols, symbol) {
+						t.Fatalf("GenerateFromAlphabetString() produced %q outside the alphabet", symbol)
+					}
+				}
+			}
+		})
+	}
+}
+
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGenerateFromAlphabetUniform(t *testing.T) {
+	symbols := SplitSymbols(thaiConsonants)
+	length := 100
+	rounds := 300
+	counts := make(map[string]int)
+
+	for i := 0; i < rounds; i++ {
+		password, err := GenerateFromAlphabet(length, symbols)
+		if err != nil {
+			t.Fatalf("GenerateFromAlphabet() unexpected error = %v", err)
+		}
+		for _, r := range password {
+			counts[string(r)]++
+		}
+	}
+
+	// Chi-squared goodness of fit against the uniform distribution. The
+	// 0.999 quantile for 29 degrees of freedom is about 58.3.
+	expected := float64(length*rounds) / float64(len(symbols))
+	chiSquared := 0.0
+	for _, symbol := range symbols {
+		diff := float64(counts[symbol]) - expected
+		chiSquared += diff * diff / expected
+	}
+	if len(counts) != len(symbols) {
+		t.Errorf("GenerateFromAlphabet() produced %d distinct runes, want %d", len(counts), len(symbols))
+	}
+	if chiSquared > 58.3 {
+		t.Errorf("GenerateFromAlphabet() chi-squared = %.1f, distribution looks non-uniform", chiSquared)
+	}
+}
+
+func TestGenerateFromAlphabetDuplicates(t *testing.T) {
+	// Duplicates must not bias the output towards "a": a biased draw would
+	// give about 3000 of 4000, an unbiased one 2000 with a deviation of 32
+	password, err := GenerateFromAlphabet(4000, []string{"a", "a", "a", "b"})
+	if err != nil {
+		t.Fatalf("GenerateFromAlphabet() unexpected error = %v", err)
+	}
+	if strings.Trim(password, "ab") != "" {
+		t.Errorf("GenerateFromAlphabet() = %q contains symbols outside the alphabet", password)
+	}
+	if a := strings.Count(password, "a"); a < 1840 || a > 2160 {
+		t.Errorf("GenerateFromAlphabet() drew %d of 4000 \"a\", want about 2000", a)
+	}
+}
+
+func TestGenerateFromAlphabetErrors(t *testing.T) {
+	if _, err := GenerateFromAlphabet(0, []string{"a"}); err == nil {
+		t.Error("GenerateFromAlphabet(0) expected error")
+	}
+	if _, err := GenerateFromAlphabet(5, nil); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("GenerateFromAlphabet(nil) error = %v, want ErrInvalidAlphabet", err)
+	}
+	if _, err := GenerateFromAlphabet(5, []string{"a", ""}); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("GenerateFromAlphabet() with empty symbol error = %v, want ErrInvalidAlphabet", err)
+	}
+	// Entries that merge with their neighbour would change the symbol count
+	for _, symbol := range []string{"ab", "\u0e31", "\u0301", "a\u200d", "\u200d", "\U0001f1fa"} {
+		if _, err := GenerateFromAlphabet(5, []string{"x", symbol}); !errors.Is(err, ErrInvalidAlphabet) {
+			t.Errorf("GenerateFromAlphabet() with symbol %q error = %v, want ErrInvalidAlphabet", symbol, err)
+		}
+	}
+	if _, err := GenerateFromAlphabetString(5, "ab\xff"); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("GenerateFromAlphabetString() with invalid UTF-8 error = %v, want ErrInvalidAlphabet", err)
+	}
+}
@@ -0,0 +1,41 @@
+package hashpassword
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// pbkdf2SHA256 derives keyLength bytes from password and salt with PBKDF2
+// (RFC 8018) using HMAC-SHA256 and the given iteration count. crypto/pbkdf2
+// only exists from Go 1.24, and golang.org/x/crypto is not a dependency.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLength + sha256.Size - 1) / sha256.Size
+	key := make([]byte, 0, blocks*sha256.Size)
+
+	var counter [4]byte
+	u := make([]byte, sha256.Size)
+	for block := 1; block <= blocks; block++ {
+		// U1 = PRF(password, salt || INT(block))
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+
+		// T = U1 xor U2 xor ... xor Uc, with Ui = PRF(password, Ui-1)
+		t := make([]byte, sha256.Size)
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLength]
+}
@@ -0,0 +1,345 @@
+package randomanimals
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCatalogJSON is the built-in catalog behind GetRandomAnimal and friends
+//
+//go:embed animals.json
+var defaultCatalogJSON []byte
+
+// defaultCatalog is parsed once from the embedded JSON and name tables
+var defaultCatalog = mustAddLocales(mustLoadCatalog(defaultCatalogJSON), localeFiles)
+
+// weightResolution is the number of random bits used for weighted picks
+const weightResolution = 53
+
+// Animal is one entry of a catalog
+type Animal struct {
+	// ID is a stable, language independent identifier such as "lion"
+	ID string `json:"id"`
+	// Name is the display name
+	Name string `json:"name"`
+	// Category groups animals, e.g. "mammal", "bird" or "fish"
+	Category string `json:"category,omitempty"`
+	// Weight is the relative likelihood of the animal in weighted picks;
+	// 0 excludes it from them. A catalog file entry without a weight gets
+	// the default weight of 1.
+	Weight float64 `json:"weight"`
+}
+
+// catalogFile is the on-disk JSON or YAML format of a catalog
+type catalogFile struct {
+	Animals []catalogAnimal `json:"animals" yaml:"animals"`
+}
+
+// catalogAnimal is an Animal in a catalog file, where a missing weight
+// must be told apart from an explicit 0
+type catalogAnimal struct {
+	ID       string   `json:"id" yaml:"id"`
+	Name     string   `json:"name" yaml:"name"`
+	Category string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Weight   *float64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// animals converts the entries of the file, defaulting missing weights to 1
+func (f *catalogFile) animals() []Animal {
+	animals := make([]Animal, len(f.Animals))
+	for i, a := range f.Animals {
+		animals[i] = Animal{ID: a.ID, Name: a.Name, Category: a.Category, Weight: 1}
+		if a.Weight != nil {
+			animals[i].Weight = *a.Weight
+		}
+	}
+	return animals
+}
+
+// Catalog is an immutable set of animals to pick from.
+// It is safe for concurrent use.
+type Catalog struct {
+	animals     []Animal
+	totalWeight float64
+	// translations maps canonical locale tags to id -> name tables
+	translations map[string]map[string]string
+}
+
+// NewCatalog creates a catalog from animals. IDs must be unique and non-empty,
+// names non-empty and weights finite and non-negative. Animals of weight 0 are still
+// picked by Random and RandomN, but never by WeightedRandom or
+// WeightedRandomN.
+func NewCatalog(animals []Animal) (*Catalog, error) {
+	if len(animals) == 0 {
+		return nil, errors.New("catalog must contain at least one animal")
+	}
+
+	c := &Catalog{animals: make([]Animal, len(animals))}
+	seen := make(map[string]bool, len(animals))
+	for i, a := range animals {
+		if a.ID == "" || a.Name == "" {
+			return nil, fmt.Errorf("animal %d: id and name cannot be empty", i)
+		}
+		if seen[a.ID] {
+			return nil, fmt.Errorf("animal %d: duplicate id %q", i, a.ID)
+		}
+		seen[a.ID] = true
+
+		if math.IsNaN(a.Weight) || math.IsInf(a.Weight, 0) {
+			return nil, fmt.Errorf("animal %q: weight must be a finite number", a.ID)
+		}
+		if a.Weight < 0 {
+			return nil, fmt.Errorf("animal %q: weight cannot be negative", a.ID)
+		}
+		c.animals[i] = a
+		c.totalWeight += a.Weight
+	}
+	return c, nil
+}
+
+// LoadCatalog reads a catalog in JSON format:
+//
+//	{"animals": [{"id": "lion", "name": "Lion", "category": "mammal", "weight": 2}]}
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	var file catalogFile
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog: %w", err)
+	}
+	return NewCatalog(file.animals())
+}
+
+// LoadCatalogYAML reads a catalog in YAML format with the same fields as
+// LoadCatalog:
+//
+//	animals:
+//	  - {id: lion, name: Lion, category: mammal, weight: 2}
+func LoadCatalogYAML(r io.Reader) (*Catalog, error) {
+	var file catalogFile
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode catalog: %w", err)
+	}
+	return NewCatalog(file.animals())
+}
+
+// LoadCatalogFile reads a catalog from path, as YAML if the file name ends
+// in .yaml or .yml and as JSON otherwise
+func LoadCatalogFile(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return LoadCatalogYAML(f)
+	}
+	return LoadCatalog(f)
+}
+
+// mustLoadCatalog parses embedded catalog data and panics if it is invalid
+func mustLoadCatalog(data []byte) *Catalog {
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		panic("randomanimals: invalid embedded catalog: " + err.Error())
+	}
+	c, err := NewCatalog(file.animals())
+	if err != nil {
+		panic("randomanimals: invalid embedded catalog: " + err.Error())
+	}
+	return c
+}
+
+// DefaultCatalog returns the built-in catalog of 10 animals
+func DefaultCatalog() *Catalog {
+	return defaultCatalog
+}
+
+// Len returns the number of animals in the catalog
+func (c *Catalog) Len() int {
+	return len(c.animals)
+}
+
+// All returns a copy of every animal in catalog order
+func (c *Catalog) All() []Animal {
+	result := make([]Animal, len(c.animals))
+	copy(result, c.animals)
+	return result
+}
+
+// Names returns the name of every animal in catalog order
+func (c *Catalog) Names() []string {
+	names := make([]string, len(c.animals))
+	for i, a := range c.animals {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// Get returns the animal with the given ID
+func (c *Catalog) Get(id string) (Animal, bool) {
+	for _, a := range c.animals {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Animal{}, false
+}
+
+// Categories returns the distinct categories in sorted order
+func (c *Catalog) Categories() []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, a := range c.animals {
+		if a.Category != "" && !seen[a.Category] {
+			seen[a.Category] = true
+			categories = append(categories, a.Category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Category returns a catalog containing only the animals of one category
+func (c *Catalog) Category(category string) (*Catalog, error) {
+	var animals []Animal
+	for _, a := range c.animals {
+		if a.Category == category {
+			animals = append(animals, a)
+		}
+	}
+	if len(animals) == 0 {
+		return nil, fmt.Errorf("no animals in category %q", category)
+	}
+	filtered, err := NewCatalog(animals)
+	if err != nil {
+		return nil, err
+	}
+	filtered.translations = c.translations
+	return filtered, nil
+}
+
+// Random returns one animal chosen uniformly, ignoring weights
+func (c *Catalog) Random() (Animal, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(c.animals))))
+	if err != nil {
+		return Animal{}, err
+	}
+	return c.animals[n.Int64()], nil
+}
+
+// RandomN returns count distinct animals chosen uniformly, ignoring weights
+func (c *Catalog) RandomN(count int) ([]Animal, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+	if count > len(c.animals) {
+		return nil, errors.New("count cannot exceed available animal types")
+	}
+
+	// Create a copy of animals slice to shuffle
+	shuffled := c.All()
+
+	// Partial Fisher-Yates shuffle using crypto/rand
+	for i := 0; i < count; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(shuffled)-i)))
+		if err != nil {
+			return nil, err
+		}
+		j := i + int(n.Int64())
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	return shuffled[:count], nil
+}
+
+// WeightedRandom returns one animal with probability proportional to its weight
+func (c *Catalog) WeightedRandom() (Animal, error) {
+	i, err := weightedIndex(c.animals, c.totalWeight)
+	if err != nil {
+		return Animal{}, err
+	}
+	return c.animals[i], nil
+}
+
+// WeightedRandomN returns count distinct animals, drawing each one with
+// probability proportional to its weight among the animals not yet drawn
+func (c *Catalog) WeightedRandomN(count int) ([]Animal, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+	if count > c.weighted() {
+		return nil, errors.New("count cannot exceed the animals with a positive weight")
+	}
+
+	remaining := c.All()
+	total := c.totalWeight
+	result := make([]Animal, 0, count)
+	for len(result) < count {
+		i, err := weightedIndex(remaining, total)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, remaining[i])
+		total -= remaining[i].Weight
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	return result, nil
+}
+
+// weighted returns the number of animals with a positive weight
+func (c *Catalog) weighted() int {
+	n := 0
+	for _, a := range c.animals {
+		if a.Weight > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// errNoWeight is returned by weighted picks when every animal has weight 0
+var errNoWeight = errors.New("no animal has a positive weight")
+
+// weightedIndex picks an index of animals with probability proportional to
+// its weight, using a uniform crypto/rand value with 53 bits of resolution
+func weightedIndex(animals []Animal, total float64) (int, error) {
+	if total <= 0 {
+		return 0, errNoWeight
+	}
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), weightResolution))
+	if err != nil {
+		return 0, err
+	}
+	target := float64(n.Int64()) / float64(uint64(1)<<weightResolution) * total
+
+	last := 0
+	for i, a := range animals {
+		if a.Weight == 0 {
+			continue
+		}
+		target -= a.Weight
+		if target < 0 {
+			return i, nil
+		}
+		last = i
+	}
+	// Floating point rounding can leave target at ~0 after the last animal
+	return last, nil
+}
@@ -0,0 +1,273 @@
+package randomanimals
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCatalogJSON = `{
+  "animals": [
+    {"id": "eagle", "name": "Eagle", "category": "bird", "weight": 1},
+    {"id": "owl", "name": "Owl", "category": "bird", "weight": 3},
+    {"id": "salmon", "name": "Salmon", "category": "fish"},
+    {"id": "shark", "name": "Shark", "category": "fish", "weight": 0.5},
+    {"id": "whale", "name": "Whale", "category": "mammal", "weight": 10.5}
+  ]
+}`
+
+func TestDefaultCatalog(t *testing.T) {
+	c := DefaultCatalog()
+	if c.Len() != 10 {
+		t.Errorf("DefaultCatalog().Len() = %d, expected 10", c.Len())
+	}
+	if cats := c.Categories(); len(cats) != 1 || cats[0] != "mammal" {
+		t.Errorf("DefaultCatalog().Categories() = %v, expected [mammal]", cats)
+	}
+	lion, ok := c.Get("lion")
+	if !ok || lion.Name != "Lion" || lion.Weight != 1 {
+		t.Errorf("DefaultCatalog().Get(\"lion\") = %+v, %v", lion, ok)
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	c, err := LoadCatalog(strings.NewReader(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("LoadCatalog() returned error: %v", err)
+	}
+	if c.Len() != 5 {
+		t.Errorf("LoadCatalog() catalog has %d animals, expected 5", c.Len())
+	}
+
+	salmon, _ := c.Get("salmon")
+	if salmon.Weight != 1 {
+		t.Errorf("Missing weight should default to 1, got %v", salmon.Weight)
+	}
+
+	want := []string{"bird", "fish", "mammal"}
+	if got := c.Categories(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Categories() = %v, expected %v", got, want)
+	}
+
+	birds, err := c.Category("bird")
+	if err != nil {
+		t.Fatalf("Category(\"bird\") returned error: %v", err)
+	}
+	if names := birds.Names(); len(names) != 2 || names[0] != "Eagle" || names[1] != "Owl" {
+		t.Errorf("Category(\"bird\").Names() = %v", names)
+	}
+	if _, err := c.Category("reptile"); err == nil {
+		t.Error("Category(\"reptile\") should return error")
+	}
+}
+
+const testCatalogYAML = `animals:
+  - {id: eagle, name: Eagle, category: bird, weight: 1}
+  - {id: owl, name: Owl, category: bird, weight: 3}
+  - id: salmon
+    name: Salmon
+    category: fish
+  - {id: shark, name: Shark, category: fish, weight: 0.5}
+  - {id: whale, name: Whale, category: mammal, weight: 10.5}
+`
+
+func TestLoadCatalogYAML(t *testing.T) {
+	c, err := LoadCatalogYAML(strings.NewReader(testCatalogYAML))
+	if err != nil {
+		t.Fatalf("LoadCatalogYAML() returned error: %v", err)
+	}
+	want, _ := LoadCatalog(strings.NewReader(testCatalogJSON))
+	if got, want := c.All(), want.All(); len(got) != len(want) {
+		t.Fatalf("LoadCatalogYAML() = %v, expected %v", got, want)
+	} else {
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("LoadCatalogYAML() animal %d = %+v, expected %+v", i, got[i], want[i])
+			}
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "animals.yml")
+	if err := os.WriteFile(path, []byte(testCatalogYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadCatalogFile(path); err != nil || c.Len() != 5 {
+		t.Errorf("LoadCatalogFile(%q) = %v, %v, expected 5 animals", path, c, err)
+	}
+
+	if _, err := LoadCatalogYAML(strings.NewReader("animals:\n  - {id: a, name: A, legs: 4}\n")); err == nil {
+		t.Error("LoadCatalogYAML() with an unknown field should return error")
+	}
+}
+
+func TestLoadCatalogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "animals.json")
+	if err := os.WriteFile(path, []byte(testCatalogJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCatalogFile(path)
+	if err != nil {
+		t.Fatalf("LoadCatalogFile() returned error: %v", err)
+	}
+	if c.Len() != 5 {
+		t.Errorf("LoadCatalogFile() catalog has %d animals, expected 5", c.Len())
+	}
+
+	if _, err := LoadCatalogFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadCatalogFile() with missing file should return error")
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":    `{"animals": [`,
+		"unknown field":   `{"animals": [{"id": "a", "name": "A", "legs": 4}]}`,
+		"empty catalog":   `{"animals": []}`,
+		"duplicate id":    `{"animals": [{"id": "a", "name": "A"}, {"id": "a", "name": "B"}]}`,
+		"missing name":    `{"animals": [{"id": "a"}]}`,
+		"negative weight": `{"animals": [{"id": "a", "name": "A", "weight": -1}]}`,
+	}
+	for name, data := range tests {
+		if _, err := LoadCatalog(strings.NewReader(data)); err == nil {
+			t.Errorf("LoadCatalog() with %s should return error", name)
+		}
+	}
+
+	// JSON cannot spell these, but YAML and callers of NewCatalog can
+	for _, w := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := NewCatalog([]Animal{{ID: "a", Name: "A", Weight: w}}); err == nil {
+			t.Errorf("NewCatalog() with weight %v should return error", w)
+		}
+	}
+	for _, w := range []string{".nan", ".inf", "-.inf"} {
+		if _, err := LoadCatalogYAML(strings.NewReader("animals: [{id: a, name: A, weight: " + w + "}]")); err == nil {
+			t.Errorf("LoadCatalogYAML() with weight %s should return error", w)
+		}
+	}
+}
+
+func TestZeroWeight(t *testing.T) {
+	c, err := LoadCatalog(strings.NewReader(`{"animals": [
+    {"id": "lion", "name": "Lion", "weight": 0},
+    {"id": "tiger", "name": "Tiger"}
+  ]}`))
+	if err != nil {
+		t.Fatalf("LoadCatalog() returned error: %v", err)
+	}
+	if lion, _ := c.Get("lion"); lion.Weight != 0 {
+		t.Errorf("Explicit weight 0 was changed to %v", lion.Weight)
+	}
+	for i := 0; i < 100; i++ {
+		if a, err := c.WeightedRandom(); err != nil || a.ID != "tiger" {
+			t.Fatalf("WeightedRandom() = %v, %v; a weight of 0 should never be picked", a.ID, err)
+		}
+	}
+	if _, err := c.WeightedRandomN(2); err == nil {
+		t.Error("WeightedRandomN(2) should return error with one positive weight")
+	}
+	if picked, _ := c.RandomN(2); len(picked) != 2 {
+		t.Errorf("RandomN(2) = %v; uniform picks should include weight 0", picked)
+	}
+
+	none, _ := NewCatalog([]Animal{{ID: "a", Name: "A"}})
+	if _, err := none.WeightedRandom(); err == nil {
+		t.Error("WeightedRandom() should return error when every weight is 0")
+	}
+}
+
+func TestCatalogLargerThanTen(t *testing.T) {
+	var animals []Animal
+	for _, name := range []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O"} {
+		animals = append(animals, Animal{ID: strings.ToLower(name), Name: name})
+	}
+	c, err := NewCatalog(animals)
+	if err != nil {
+		t.Fatalf("NewCatalog() returned error: %v", err)
+	}
+
+	picked, err := c.RandomN(15)
+	if err != nil {
+		t.Fatalf("RandomN(15) returned error: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, a := range picked {
+		if seen[a.ID] {
+			t.Errorf("RandomN() returned duplicate animal: %s", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	if len(seen) != 15 {
+		t.Errorf("RandomN(15) returned %d distinct animals, expected 15", len(seen))
+	}
+
+	if _, err := c.RandomN(16); err == nil {
+		t.Error("RandomN(16) should return error")
+	}
+}
+
+func TestWeightedRandom(t *testing.T) {
+	c, err := LoadCatalog(strings.NewReader(testCatalogJSON))
+	if err != nil {
+		t.Fatalf("LoadCatalog() returned error: %v", err)
+	}
+
+	// Total weight is 16, so whale should come up ~65.6% and shark ~3.1% of the time
+	counts := make(map[string]int)
+	iterations := 10000
+	for i := 0; i < iterations; i++ {
+		a, err := c.WeightedRandom()
+		if err != nil {
+			t.Fatalf("WeightedRandom() returned error: %v", err)
+		}
+		counts[a.ID]++
+	}
+
+	expected := map[string]float64{"eagle": 1, "owl": 3, "salmon": 1, "shark": 0.5, "whale": 10.5}
+	for id, weight := range expected {
+		want := weight / 16 * float64(iterations)
+		got := float64(counts[id])
+		// Allow five standard deviations of the binomial distribution
+		p := weight / 16
+		tolerance := 5 * math.Sqrt(float64(iterations)*p*(1-p))
+		if got < want-tolerance || got > want+tolerance {
+			t.Errorf("WeightedRandom() picked %s %v times, expected about %.0f", id, got, want)
+		}
+	}
+}
+
+func TestWeightedRandomN(t *testing.T) {
+	c, _ := LoadCatalog(strings.NewReader(testCatalogJSON))
+
+	all, err := c.WeightedRandomN(5)
+	if err != nil {
+		t.Fatalf("WeightedRandomN(5) returned error: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, a := range all {
+		seen[a.ID] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("WeightedRandomN(5) returned %d distinct animals, expected 5", len(seen))
+	}
+
+	// The heavy whale should usually be drawn first
+	whaleFirst := 0
+	for i := 0; i < 1000; i++ {
+		picked, _ := c.WeightedRandomN(2)
+		if picked[0].ID == "whale" {
+			whaleFirst++
+		}
+	}
+	if whaleFirst < 550 || whaleFirst > 760 {
+		t.Errorf("WeightedRandomN() drew whale first %d/1000 times, expected about 656", whaleFirst)
+	}
+
+	if _, err := c.WeightedRandomN(0); err == nil {
+		t.Error("WeightedRandomN(0) should return error")
+	}
+	if _, err := c.WeightedRandomN(6); err == nil {
+		t.Error("WeightedRandomN(6) should return error")
+	}
+}
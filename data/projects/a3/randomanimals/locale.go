@@ -0,0 +1,170 @@
+package randomanimals
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale of Animal.Name and the end of every fallback chain
+const DefaultLocale = "en"
+
+// localeFiles holds the built-in name tables, one JSON object of id -> name per locale
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// CanonicalLocale normalizes a BCP 47 tag for comparison: lower case, "-"
+// separated, with extension and private use subtags (from the first single
+// letter subtag on) removed. "th_TH" and "TH-th" both become "th-th".
+func CanonicalLocale(locale string) string {
+	subtags := strings.FieldsFunc(strings.ToLower(locale), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	for i, subtag := range subtags {
+		if len(subtag) == 1 {
+			subtags = subtags[:i]
+			break
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
+// LocaleFallbacks returns the lookup chain for a locale, most specific
+// first and always ending in DefaultLocale, e.g.
+// "zh-Hant-TW" -> ["zh-hant-tw", "zh-hant", "zh", "en"]
+func LocaleFallbacks(locale string) []string {
+	var chain []string
+	for tag := CanonicalLocale(locale); tag != ""; {
+		chain = append(chain, tag)
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	if len(chain) == 0 || chain[len(chain)-1] != DefaultLocale {
+		chain = append(chain, DefaultLocale)
+	}
+	return chain
+}
+
+// LoadTranslations reads a name table for one locale in JSON format, mapping
+// animal IDs to localized names: {"lion": "สิงโต", "tiger": "เสือ"}
+func LoadTranslations(r io.Reader) (map[string]string, error) {
+	var names map[string]string
+	if err := json.NewDecoder(r).Decode(&names); err != nil {
+		return nil, fmt.Errorf("failed to decode translations: %w", err)
+	}
+	return names, nil
+}
+
+// WithTranslations returns a copy of the catalog with a name table added for
+// locale, replacing any existing table for the same locale. Every key must be
+// the ID of an animal in the catalog; animals missing from the table fall
+// back along the locale's fallback chain.
+func (c *Catalog) WithTranslations(locale string, names map[string]string) (*Catalog, error) {
+	tag := CanonicalLocale(locale)
+	if tag == "" {
+		return nil, fmt.Errorf("invalid locale %q", locale)
+	}
+
+	table := make(map[string]string, len(names))
+	for id, name := range names {
+		if _, ok := c.Get(id); !ok {
+			return nil, fmt.Errorf("locale %s: unknown animal id %q", tag, id)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("locale %s: empty name for %q", tag, id)
+		}
+		table[id] = name
+	}
+
+	result := &Catalog{
+		animals:      c.animals,
+		totalWeight:  c.totalWeight,
+		translations: make(map[string]map[string]string, len(c.translations)+1),
+	}
+	for existing, t := range c.translations {
+		result.translations[existing] = t
+	}
+	result.translations[tag] = table
+	return result, nil
+}
+
+// Locales returns the locales with a name table, plus DefaultLocale, sorted
+func (c *Catalog) Locales() []string {
+	locales := []string{DefaultLocale}
+	for tag := range c.translations {
+		if tag != DefaultLocale {
+			locales = append(locales, tag)
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocalizedName returns the animal's name in the best available locale of
+// the fallback chain, ending with Animal.Name
+func (c *Catalog) LocalizedName(a Animal, locale string) string {
+	for _, tag := range LocaleFallbacks(locale) {
+		if name, ok := c.translations[tag][a.ID]; ok {
+			return name
+		}
+	}
+	return a.Name
+}
+
+// LocalizedNames returns every animal's localized name in catalog order
+func (c *Catalog) LocalizedNames(locale string) []string {
+	names := make([]string, len(c.animals))
+	for i, a := range c.animals {
+		names[i] = c.LocalizedName(a, locale)
+	}
+	return names
+}
+
+// mustAddLocales adds every embedded locales/<tag>.json table to c and
+// panics if one is invalid
+func mustAddLocales(c *Catalog, files fs.FS) *Catalog {
+	paths, err := fs.Glob(files, "locales/*.json")
+	if err != nil {
+		panic("randomanimals: invalid embedded locales: " + err.Error())
+	}
+	for _, p := range paths {
+		f, err := files.Open(p)
+		if err != nil {
+			panic("randomanimals: invalid embedded locales: " + err.Error())
+		}
+		names, err := LoadTranslations(f)
+		f.Close()
+		if err == nil {
+			c, err = c.WithTranslations(strings.TrimSuffix(path.Base(p), ".json"), names)
+		}
+		if err != nil {
+			panic("randomanimals: invalid embedded locale " + p + ": " + err.Error())
+		}
+	}
+	return c
+}
+
+// GetRandomAnimalLocalized returns one random animal name from the default
+// catalog in the given locale, e.g. "th-TH" or "ja"
+func GetRandomAnimalLocalized(locale string) (string, error) {
+	animal, err := defaultCatalog.Random()
+	if err != nil {
+		return "", err
+	}
+	return defaultCatalog.LocalizedName(animal, locale), nil
+}
+
+// GetAllAnimalsLocalized returns all animal names of the default catalog in
+// the given locale
+func GetAllAnimalsLocalized(locale string) []string {
+	return defaultCatalog.LocalizedNames(locale)
+}
@@ -0,0 +1,142 @@
+package randomanimals
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{locale: "th-TH", want: []string{"th-th", "th", "en"}},
+		{locale: "th_TH", want: []string{"th-th", "th", "en"}},
+		{locale: "ja", want: []string{"ja", "en"}},
+		{locale: "zh-Hant-TW", want: []string{"zh-hant-tw", "zh-hant", "zh", "en"}},
+		{locale: "de-DE-u-co-phonebk", want: []string{"de-de", "de", "en"}},
+		{locale: "en-US", want: []string{"en-us", "en"}},
+		{locale: "", want: []string{"en"}},
+	}
+
+	for _, tt := range tests {
+		got := LocaleFallbacks(tt.locale)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("LocaleFallbacks(%q) = %v, expected %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizedDefaultCatalog(t *testing.T) {
+	c := DefaultCatalog()
+	lion, _ := c.Get("lion")
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "th-TH", want: "สิงโต"},
+		{locale: "th", want: "สิงโต"},
+		{locale: "ja-JP", want: "ライオン"},
+		{locale: "fr-FR", want: "Lion"},
+		{locale: "en", want: "Lion"},
+	}
+	for _, tt := range tests {
+		if got := c.LocalizedName(lion, tt.locale); got != tt.want {
+			t.Errorf("LocalizedName(lion, %q) = %q, expected %q", tt.locale, got, tt.want)
+		}
+	}
+
+	want := []string{"en", "ja", "th"}
+	if got := c.Locales(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Locales() = %v, expected %v", got, want)
+	}
+}
+
+func TestGetAllAnimalsLocalized(t *testing.T) {
+	thai := GetAllAnimalsLocalized("th-TH")
+	english := GetAllAnimals()
+	if len(thai) != len(english) {
+		t.Fatalf("GetAllAnimalsLocalized() returned %d animals, expected %d", len(thai), len(english))
+	}
+	for i := range thai {
+		if thai[i] == english[i] {
+			t.Errorf("GetAllAnimalsLocalized(\"th-TH\")[%d] = %q was not translated", i, thai[i])
+		}
+	}
+
+	// Unknown locales fall back to English
+	if got := GetAllAnimalsLocalized("sw"); strings.Join(got, ",") != strings.Join(english, ",") {
+		t.Errorf("GetAllAnimalsLocalized(\"sw\") = %v, expected English names", got)
+	}
+}
+
+func TestGetRandomAnimalLocalized(t *testing.T) {
+	japanese := GetAllAnimalsLocalized("ja")
+	animal, err := GetRandomAnimalLocalized("ja-JP")
+	if err != nil {
+		t.Fatalf("GetRandomAnimalLocalized() returned error: %v", err)
+	}
+
+	found := false
+	for _, name := range japanese {
+		if name == animal {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("GetRandomAnimalLocalized(\"ja-JP\") returned %q, not a Japanese name", animal)
+	}
+}
+
+func TestWithTranslations(t *testing.T) {
+	c := DefaultCatalog()
+
+	// A regional table only overrides what it defines
+	regional, err := c.WithTranslations("th-TH", map[string]string{"lion": "ราชสีห์"})
+	if err != nil {
+		t.Fatalf("WithTranslations() returned error: %v", err)
+	}
+	lion, _ := c.Get("lion")
+	tiger, _ := c.Get("tiger")
+	if got := regional.LocalizedName(lion, "th-TH"); got != "ราชสีห์" {
+		t.Errorf("LocalizedName(lion, \"th-TH\") = %q, expected regional name", got)
+	}
+	if got := regional.LocalizedName(tiger, "th-TH"); got != "เสือ" {
+		t.Errorf("LocalizedName(tiger, \"th-TH\") = %q, expected fallback to th", got)
+	}
+	if got := regional.LocalizedName(lion, "th"); got != "สิงโต" {
+		t.Errorf("LocalizedName(lion, \"th\") = %q, expected the th table", got)
+	}
+
+	// The original catalog is unchanged
+	if got := c.LocalizedName(lion, "th-TH"); got != "สิงโต" {
+		t.Errorf("WithTranslations() modified the original catalog: %q", got)
+	}
+
+	if _, err := c.WithTranslations("th", map[string]string{"dragon": "มังกร"}); err == nil {
+		t.Error("WithTranslations() with unknown id should return error")
+	}
+	if _, err := c.WithTranslations("", map[string]string{"lion": "x"}); err == nil {
+		t.Error("WithTranslations() with empty locale should return error")
+	}
+}
+
+func TestLoadTranslations(t *testing.T) {
+	names, err := LoadTranslations(strings.NewReader(`{"lion": "Löwe", "fox": "Fuchs"}`))
+	if err != nil {
+		t.Fatalf("LoadTranslations() returned error: %v", err)
+	}
+	c, err := DefaultCatalog().WithTranslations("de", names)
+	if err != nil {
+		t.Fatalf("WithTranslations() returned error: %v", err)
+	}
+	fox, _ := c.Get("fox")
+	if got := c.LocalizedName(fox, "de-AT"); got != "Fuchs" {
+		t.Errorf("LocalizedName(fox, \"de-AT\") = %q, expected Fuchs", got)
+	}
+
+	if _, err := LoadTranslations(strings.NewReader(`["lion"]`)); err == nil {
+		t.Error("LoadTranslations() with a JSON array should return error")
+	}
+}
@@ -0,0 +1,192 @@
+package randomanimals
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// adjectives is the default list for human-readable names
+var adjectives = []string{
+	"agile", "bold", "brave", "bright", "calm", "clever", "cosmic", "curious",
+	"daring", "eager", "fancy", "fierce", "gentle", "happy", "hidden", "jolly",
+	"keen", "kind", "lively", "lucky", "mighty", "misty", "nimble", "noble",
+	"proud", "quick", "quiet", "rapid", "shiny", "silent", "swift", "witty",
+}
+
+const (
+	// DefaultNamePattern is the Docker/Heroku style adjective-animal-number pattern
+	DefaultNamePattern = "{adjective}{sep}{animal}{sep}{number}"
+	// DefaultNameSeparator joins the parts of a name
+	DefaultNameSeparator = "-"
+	// DefaultMaxRetries is how many names are drawn before giving up on collisions
+	DefaultMaxRetries = 10
+)
+
+// ErrNameCollision is returned when every attempt produced a taken name
+var ErrNameCollision = errors.New("could not generate an untaken name")
+
+// NameGenerator builds names such as "brave-tiger-42" from a pattern.
+// Placeholders are {adjective}, {animal}, {number} and {sep}; animal names are
+// lower-cased and spaces replaced by the separator.
+type NameGenerator struct {
+	Pattern    string
+	Separator  string
+	Adjectives []string
+	// Catalog supplies the animals; nil means the default catalog
+	Catalog *Catalog
+	// MinNumber and MaxNumber bound the inclusive numeric suffix range
+	MinNumber int
+	MaxNumber int
+	// MaxRetries bounds the attempts made to avoid taken names
+	MaxRetries int
+}
+
+// NewNameGenerator returns a generator for adjective-animal-number names with
+// numbers from 1 to 99
+func NewNameGenerator() *NameGenerator {
+	return &NameGenerator{
+		Pattern:    DefaultNamePattern,
+		Separator:  DefaultNameSeparator,
+		Adjectives: GetAdjectives(),
+		Catalog:    defaultCatalog,
+		MinNumber:  1,
+		MaxNumber:  99,
+		MaxRetries: DefaultMaxRetries,
+	}
+}
+
+// GetAdjectives returns the default adjective list
+func GetAdjectives() []string {
+	result := make([]string, len(adjectives))
+	copy(result, adjectives)
+	return result
+}
+
+// catalog returns the configured catalog or the default one
+func (g *NameGenerator) catalog() *Catalog {
+	if g.Catalog == nil {
+		return defaultCatalog
+	}
+	return g.Catalog
+}
+
+// uses reports whether the pattern contains the placeholder
+func (g *NameGenerator) uses(placeholder string) bool {
+	return strings.Contains(g.Pattern, "{"+placeholder+"}")
+}
+
+// validate checks that the generator can produce names
+func (g *NameGenerator) validate() error {
+	if g.Pattern == "" {
+		return errors.New("name pattern cannot be empty")
+	}
+	if g.uses("adjective") && len(g.Adjectives) == 0 {
+		return errors.New("pattern uses {adjective} but no adjectives are configured")
+	}
+	if g.uses("number") && g.MinNumber > g.MaxNumber {
+		return errors.New("minimum number cannot exceed maximum number")
+	}
+	return nil
+}
+
+// NameSpaceSize returns the number of distinct names the generator can
+// produce, assuming adjectives and animals are distinct
+func (g *NameGenerator) NameSpaceSize() *big.Int {
+	size := big.NewInt(1)
+	if g.uses("adjective") {
+		size.Mul(size, big.NewInt(int64(len(g.Adjectives))))
+	}
+	if g.uses("animal") {
+		size.Mul(size, big.NewInt(int64(g.catalog().Len())))
+	}
+	if g.uses("number") && g.MaxNumber >= g.MinNumber {
+		size.Mul(size, g.numberSpan())
+	}
+	return size
+}
+
+// numberSpan returns the count of numbers in [MinNumber, MaxNumber], which
+// can exceed an int64 when the range covers most of int
+func (g *NameGenerator) numberSpan() *big.Int {
+	span := new(big.Int).Sub(big.NewInt(int64(g.MaxNumber)), big.NewInt(int64(g.MinNumber)))
+	return span.Add(span, big.NewInt(1))
+}
+
+// CollisionProbability returns the birthday-problem probability that at
+// least two of count independently generated names are equal,
+// 1 - exp(-n(n-1) / 2N) for a name space of size N
+func (g *NameGenerator) CollisionProbability(count int) float64 {
+	if count < 2 {
+		return 0
+	}
+	space, _ := new(big.Float).SetInt(g.NameSpaceSize()).Float64()
+	if space == 0 {
+		return 1
+	}
+	n := float64(count)
+	return -math.Expm1(-n * (n - 1) / (2 * space))
+}
+
+// Generate returns one random name
+func (g *NameGenerator) Generate() (string, error) {
+	if err := g.validate(); err != nil {
+		return "", err
+	}
+
+	replacements := []string{"{sep}", g.Separator}
+	if g.uses("adjective") {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(g.Adjectives))))
+		if err != nil {
+			return "", err
+		}
+		replacements = append(replacements, "{adjective}", g.Adjectives[n.Int64()])
+	}
+	if g.uses("animal") {
+		animal, err := g.catalog().Random()
+		if err != nil {
+			return "", err
+		}
+		name := strings.Join(strings.Fields(strings.ToLower(animal.Name)), g.Separator)
+		replacements = append(replacements, "{animal}", name)
+	}
+	if g.uses("number") {
+		n, err := rand.Int(rand.Reader, g.numberSpan())
+		if err != nil {
+			return "", err
+		}
+		n.Add(n, big.NewInt(int64(g.MinNumber)))
+		replacements = append(replacements, "{number}", n.String())
+	}
+
+	return strings.NewReplacer(replacements...).Replace(g.Pattern), nil
+}
+
+// GenerateUnique returns a name for which taken reports false, retrying up
+// to MaxRetries times. taken is typically a lookup in the caller's set of
+// existing names.
+func (g *NameGenerator) GenerateUnique(taken func(string) bool) (string, error) {
+	retries := g.MaxRetries
+	if retries <= 0 {
+		retries = DefaultMaxRetries
+	}
+
+	for attempt := 0; attempt < retries; attempt++ {
+		name, err := g.Generate()
+		if err != nil {
+			return "", err
+		}
+		if taken == nil || !taken(name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%w after %d attempts", ErrNameCollision, retries)
+}
+
+// GenerateName returns a default adjective-animal-number name such as "brave-tiger-42"
+func GenerateName() (string, error) {
+	return NewNameGenerator().Generate()
+}
@@ -0,0 +1,163 @@
+package randomanimals
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerateName(t *testing.T) {
+	format := regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]{1,2}$`)
+	for i := 0; i < 100; i++ {
+		name, err := GenerateName()
+		if err != nil {
+			t.Fatalf("GenerateName() returned error: %v", err)
+		}
+		if !format.MatchString(name) {
+			t.Fatalf("GenerateName() = %q, expected adjective-animal-number", name)
+		}
+
+		parts := strings.Split(name, "-")
+		n, _ := strconv.Atoi(parts[2])
+		if n < 1 || n > 99 {
+			t.Errorf("GenerateName() number %d outside 1..99", n)
+		}
+	}
+}
+
+func TestNameGeneratorPattern(t *testing.T) {
+	g := NewNameGenerator()
+	g.Pattern = "{animal}{sep}{adjective}"
+	g.Separator = "_"
+
+	name, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !regexp.MustCompile(`^[a-z]+_[a-z]+$`).MatchString(name) {
+		t.Errorf("Generate() = %q, expected animal_adjective", name)
+	}
+
+	g.Pattern = "sandbox-{number}"
+	g.MinNumber, g.MaxNumber = 1000, 1000
+	if name, _ := g.Generate(); name != "sandbox-1000" {
+		t.Errorf("Generate() = %q, expected sandbox-1000", name)
+	}
+
+	// The span of the whole int range does not fit in an int64
+	g.Pattern = "{number}"
+	g.MinNumber, g.MaxNumber = math.MinInt, math.MaxInt
+	for i := 0; i < 100; i++ {
+		name, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() over the whole int range returned error: %v", err)
+		}
+		if _, err := strconv.Atoi(name); err != nil {
+			t.Fatalf("Generate() = %q, expected an int: %v", name, err)
+		}
+	}
+}
+
+func TestNameGeneratorMultiWordAnimal(t *testing.T) {
+	c, err := NewCatalog([]Animal{{ID: "red-panda", Name: "Red Panda"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewNameGenerator()
+	g.Catalog = c
+	g.Pattern = "{animal}"
+	g.Separator = "."
+
+	if name, _ := g.Generate(); name != "red.panda" {
+		t.Errorf("Generate() = %q, expected red.panda", name)
+	}
+}
+
+func TestNameGeneratorErrors(t *testing.T) {
+	g := NewNameGenerator()
+	g.MinNumber, g.MaxNumber = 10, 5
+	if _, err := g.Generate(); err == nil {
+		t.Error("Generate() with inverted number range should return error")
+	}
+
+	g = NewNameGenerator()
+	g.Adjectives = nil
+	if _, err := g.Generate(); err == nil {
+		t.Error("Generate() without adjectives should return error")
+	}
+
+	g = NewNameGenerator()
+	g.Pattern = ""
+	if _, err := g.Generate(); err == nil {
+		t.Error("Generate() with empty pattern should return error")
+	}
+}
+
+func TestGenerateUnique(t *testing.T) {
+	g := NewNameGenerator()
+	g.MinNumber, g.MaxNumber = 1, 1
+	g.Adjectives = []string{"brave"}
+	g.Catalog, _ = DefaultCatalog().Category("mammal")
+
+	// Take every name but brave-tiger-1
+	taken := func(name string) bool { return name != "brave-tiger-1" }
+	g.MaxRetries = 1000
+	name, err := g.GenerateUnique(taken)
+	if err != nil {
+		t.Fatalf("GenerateUnique() returned error: %v", err)
+	}
+	if name != "brave-tiger-1" {
+		t.Errorf("GenerateUnique() = %q, expected the only free name", name)
+	}
+
+	g.MaxRetries = 5
+	_, err = g.GenerateUnique(func(string) bool { return true })
+	if !errors.Is(err, ErrNameCollision) {
+		t.Errorf("GenerateUnique() error = %v, expected ErrNameCollision", err)
+	}
+
+	existing := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		name, err := NewNameGenerator().GenerateUnique(func(n string) bool { return existing[n] })
+		if err != nil {
+			t.Fatalf("GenerateUnique() returned error: %v", err)
+		}
+		existing[name] = true
+	}
+	if len(existing) != 5 {
+		t.Errorf("GenerateUnique() produced %d distinct names, expected 5", len(existing))
+	}
+}
+
+func TestNameSpaceSize(t *testing.T) {
+	g := NewNameGenerator()
+	// 32 adjectives * 10 animals * 99 numbers
+	if got := g.NameSpaceSize().Int64(); got != 31680 {
+		t.Errorf("NameSpaceSize() = %d, expected 31680", got)
+	}
+
+	g.Pattern = "{adjective}{sep}{animal}"
+	if got := g.NameSpaceSize().Int64(); got != 320 {
+		t.Errorf("NameSpaceSize() = %d, expected 320", got)
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	g := NewNameGenerator()
+
+	if p := g.CollisionProbability(1); p != 0 {
+		t.Errorf("CollisionProbability(1) = %v, expected 0", p)
+	}
+
+	// 1 - exp(-100*99 / (2*31680)) ~= 0.1447
+	if p := g.CollisionProbability(100); math.Abs(p-0.1447) > 0.001 {
+		t.Errorf("CollisionProbability(100) = %v, expected ~0.1447", p)
+	}
+
+	if p := g.CollisionProbability(10000); p < 0.999 {
+		t.Errorf("CollisionProbability(10000) = %v, expected ~1", p)
+	}
+}
@@ -0,0 +1,36 @@
+package randomanimals
+
+// animals is the list of animal names in the default catalog
+var animals = defaultCatalog.Names()
+
+// GetRandomAnimal returns one random animal from the default catalog
+// Uses crypto/rand for secure random selection
+func GetRandomAnimal() (string, error) {
+	animal, err := defaultCatalog.Random()
+	if err != nil {
+		return "", err
+	}
+	return animal.Name, nil
+}
+
+// GetRandomAnimals returns multiple unique random animals
+// count: number of unique animals to return (max: size of the default catalog)
+// Returns error if count <= 0 or count exceeds the catalog size
+func GetRandomAnimals(count int) ([]string, error) {
+	picked, err := defaultCatalog.RandomN(count)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(picked))
+	for i, animal := range picked {
+		result[i] = animal.Name
+	}
+	return result, nil
+}
+
+// GetAllAnimals returns all animals of the default catalog
+func GetAllAnimals() []string {
+	// Names builds a fresh slice, so callers cannot modify the catalog
+	return defaultCatalog.Names()
+}
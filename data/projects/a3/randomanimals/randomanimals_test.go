@@ -2,6 +2,8 @@ package randomanimals
 
 import (
 	"testing"
+
+	"github.com/example/hashpassword/randtest"
 )
 
 func TestGetRandomAnimal(t *testing.T) {
@@ -132,21 +134,18 @@ func TestGetAllAnimals(t *testing.T) {
 }
 
 func TestGetRandomAnimalDistribution(t *testing.T) {
-	// Run multiple times to ensure we're getting variety
-	// This is a basic sanity check, not a statistical test
-	results := make(map[string]int)
-	iterations := 100
-
-	for i := 0; i < iterations; i++ {
-		animal, err := GetRandomAnimal()
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(2000, func() ([]string, error) {
+			animal, err := GetRandomAnimal()
+			return []string{animal}, err
+		})
 		if err != nil {
-			t.Fatalf("GetRandomAnimal() returned error: %v", err)
+			return nil, err
 		}
-		results[animal]++
-	}
-
-	// We should see at least a few different animals in 100 iterations
-	if len(results) < 3 {
-		t.Errorf("GetRandomAnimal() seems biased, only got %d unique animals in %d iterations", len(results), iterations)
-	}
+		counts, err := randtest.Counts(samples, animals)
+		if err != nil {
+			return nil, err
+		}
+		return []randtest.Result{randtest.UniformityTest("GetRandomAnimal", counts)}, nil
+	})
 }
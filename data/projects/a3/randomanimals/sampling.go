@@ -0,0 +1,145 @@
+package randomanimals
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"math/bits"
+	randv2 "math/rand/v2"
+)
+
+// Rand is the randomness behind Shuffle and Sample. The zero value and nil
+// use crypto/rand; NewSeededRand returns a deterministic ChaCha8 stream.
+// A seeded Rand is not safe for concurrent use.
+type Rand struct {
+	chacha *randv2.ChaCha8
+}
+
+// NewSeededRand returns a deterministic Rand: the same seed produces the same
+// shuffles and samples on every platform and Go version, because index
+// derivation only depends on the ChaCha8 output stream
+func NewSeededRand(seed [32]byte) *Rand {
+	return &Rand{chacha: randv2.NewChaCha8(seed)}
+}
+
+// SeedFromString derives a 32 byte seed from a human readable value such as
+// a game round ID
+func SeedFromString(s string) [32]byte {
+	return sha256.Sum256([]byte(s))
+}
+
+// IntN returns a uniformly distributed integer in [0, n)
+func (r *Rand) IntN(n int) (int, error) {
+	if n <= 0 {
+		return 0, errors.New("n must be greater than 0")
+	}
+	if r == nil || r.chacha == nil {
+		v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+		if err != nil {
+			return 0, err
+		}
+		return int(v.Int64()), nil
+	}
+	return int(r.uint64n(uint64(n))), nil
+}
+
+// uint64n is Lemire's nearly divisionless unbiased bounded integer method
+func (r *Rand) uint64n(n uint64) uint64 {
+	hi, lo := bits.Mul64(r.chacha.Uint64(), n)
+	if lo < n {
+		threshold := -n % n
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.chacha.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// Shuffle permutes items in place with a Fisher-Yates shuffle
+func Shuffle[T any](r *Rand, items []T) error {
+	for i := len(items) - 1; i > 0; i-- {
+		j, err := r.IntN(i + 1)
+		if err != nil {
+			return err
+		}
+		items[i], items[j] = items[j], items[i]
+	}
+	return nil
+}
+
+// Sample returns count distinct elements of items (by position) in random
+// order, without modifying items
+func Sample[T any](r *Rand, items []T, count int) ([]T, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+	if count > len(items) {
+		return nil, errors.New("count cannot exceed the number of items")
+	}
+
+	// Partial Fisher-Yates shuffle over a copy
+	shuffled := make([]T, len(items))
+	copy(shuffled, items)
+	for i := 0; i < count; i++ {
+		j, err := r.IntN(len(shuffled) - i)
+		if err != nil {
+			return nil, err
+		}
+		shuffled[i], shuffled[i+j] = shuffled[i+j], shuffled[i]
+	}
+	return shuffled[:count], nil
+}
+
+// SampleWithReplacement returns count elements of items drawn independently,
+// so the same element may appear more than once and count may exceed len(items)
+func SampleWithReplacement[T any](r *Rand, items []T, count int) ([]T, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+	if len(items) == 0 {
+		return nil, errors.New("cannot sample from an empty slice")
+	}
+
+	result := make([]T, count)
+	for i := range result {
+		j, err := r.IntN(len(items))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = items[j]
+	}
+	return result, nil
+}
+
+// Sample returns count distinct animals using r (nil means crypto/rand)
+func (c *Catalog) Sample(r *Rand, count int) ([]Animal, error) {
+	return Sample(r, c.animals, count)
+}
+
+// SampleWithReplacement returns count animals drawn independently using r
+// (nil means crypto/rand); count may exceed the catalog size
+func (c *Catalog) SampleWithReplacement(r *Rand, count int) ([]Animal, error) {
+	return SampleWithReplacement(r, c.animals, count)
+}
+
+// Shuffled returns all animals in an order determined by r (nil means crypto/rand)
+func (c *Catalog) Shuffled(r *Rand) ([]Animal, error) {
+	animals := c.All()
+	if err := Shuffle(r, animals); err != nil {
+		return nil, err
+	}
+	return animals, nil
+}
+
+// GetRandomAnimalsWithReplacement returns count random animal names from the
+// default catalog where repeats are allowed, so count is not limited to its size
+func GetRandomAnimalsWithReplacement(count int) ([]string, error) {
+	return SampleWithReplacement(nil, animals, count)
+}
+
+// GetRandomAnimalsSeeded is GetRandomAnimals with a deterministic seed:
+// identical seeds return identical animals in identical order
+func GetRandomAnimalsSeeded(seed [32]byte, count int) ([]string, error) {
+	return Sample(NewSeededRand(seed), animals, count)
+}
@@ -0,0 +1,171 @@
+package randomanimals
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSeededReproducible(t *testing.T) {
+	seed := SeedFromString("round-1")
+
+	first, err := GetRandomAnimalsSeeded(seed, 5)
+	if err != nil {
+		t.Fatalf("GetRandomAnimalsSeeded() returned error: %v", err)
+	}
+	second, _ := GetRandomAnimalsSeeded(seed, 5)
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Errorf("GetRandomAnimalsSeeded() not reproducible: %v vs %v", first, second)
+	}
+
+	other, _ := GetRandomAnimalsSeeded(SeedFromString("round-2"), 10)
+	all, _ := GetRandomAnimalsSeeded(SeedFromString("round-1"), 10)
+	if strings.Join(other, ",") == strings.Join(all, ",") {
+		t.Error("GetRandomAnimalsSeeded() returned the same order for different seeds")
+	}
+}
+
+func TestSeededGoldenValues(t *testing.T) {
+	// These values must never change: saved game rounds depend on them
+	animals, _ := GetRandomAnimalsSeeded(SeedFromString("round-1"), 5)
+	if got := fmt.Sprint(animals); got != "[Deer Rabbit Lion Monkey Giraffe]" {
+		t.Errorf("GetRandomAnimalsSeeded(round-1) = %s", got)
+	}
+
+	ints := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if err := Shuffle(NewSeededRand([32]byte{}), ints); err != nil {
+		t.Fatalf("Shuffle() returned error: %v", err)
+	}
+	if got := fmt.Sprint(ints); got != "[10 8 3 5 2 6 9 1 4 7]" {
+		t.Errorf("Shuffle(zero seed) = %s", got)
+	}
+
+	rolls, _ := SampleWithReplacement(NewSeededRand(SeedFromString("dice")), []int{1, 2, 3, 4, 5, 6}, 10)
+	if got := fmt.Sprint(rolls); got != "[3 5 4 4 4 1 6 4 2 6]" {
+		t.Errorf("SampleWithReplacement(dice) = %s", got)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	if err := Shuffle(nil, items); err != nil {
+		t.Fatalf("Shuffle() returned error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range items {
+		seen[item] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("Shuffle() lost elements: %v", items)
+	}
+
+	// Every permutation of 3 elements should appear
+	perms := make(map[string]int)
+	r := NewSeededRand(SeedFromString("perms"))
+	for i := 0; i < 600; i++ {
+		p := []int{1, 2, 3}
+		Shuffle(r, p)
+		perms[fmt.Sprint(p)]++
+	}
+	if len(perms) != 6 {
+		t.Errorf("Shuffle() produced %d of 6 permutations", len(perms))
+	}
+	for perm, n := range perms {
+		if n < 60 || n > 140 {
+			t.Errorf("Shuffle() produced %s %d times, expected about 100", perm, n)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	original := fmt.Sprint(items)
+
+	sample, err := Sample(nil, items, 4)
+	if err != nil {
+		t.Fatalf("Sample() returned error: %v", err)
+	}
+	if len(sample) != 4 {
+		t.Errorf("Sample() returned %d items, expected 4", len(sample))
+	}
+	seen := make(map[int]bool)
+	for _, v := range sample {
+		if seen[v] {
+			t.Errorf("Sample() returned duplicate %d", v)
+		}
+		seen[v] = true
+	}
+	if fmt.Sprint(items) != original {
+		t.Error("Sample() modified its input")
+	}
+
+	if _, err := Sample(nil, items, 9); err == nil {
+		t.Error("Sample() with count > len should return error")
+	}
+	if _, err := Sample(nil, items, 0); err == nil {
+		t.Error("Sample() with count 0 should return error")
+	}
+}
+
+func TestSampleWithReplacement(t *testing.T) {
+	names, err := GetRandomAnimalsWithReplacement(50)
+	if err != nil {
+		t.Fatalf("GetRandomAnimalsWithReplacement() returned error: %v", err)
+	}
+	if len(names) != 50 {
+		t.Errorf("GetRandomAnimalsWithReplacement(50) returned %d animals", len(names))
+	}
+	for _, name := range names {
+		found := false
+		for _, a := range animals {
+			if a == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("GetRandomAnimalsWithReplacement() returned invalid animal: %s", name)
+		}
+	}
+
+	if _, err := SampleWithReplacement[int](nil, nil, 3); err == nil {
+		t.Error("SampleWithReplacement() from empty slice should return error")
+	}
+	if _, err := GetRandomAnimalsWithReplacement(0); err == nil {
+		t.Error("GetRandomAnimalsWithReplacement(0) should return error")
+	}
+}
+
+func TestCatalogSampling(t *testing.T) {
+	c := DefaultCatalog()
+	seed := SeedFromString("catalog")
+
+	a, _ := c.Shuffled(NewSeededRand(seed))
+	b, _ := c.Shuffled(NewSeededRand(seed))
+	if fmt.Sprint(a) != fmt.Sprint(b) || len(a) != c.Len() {
+		t.Error("Shuffled() with the same seed returned different orders")
+	}
+
+	picked, err := c.SampleWithReplacement(NewSeededRand(seed), 25)
+	if err != nil || len(picked) != 25 {
+		t.Errorf("SampleWithReplacement(25) = %d animals, %v", len(picked), err)
+	}
+	if _, err := c.Sample(nil, 11); err == nil {
+		t.Error("Sample(11) should return error")
+	}
+}
+
+func TestIntN(t *testing.T) {
+	var r *Rand
+	if _, err := r.IntN(0); err == nil {
+		t.Error("IntN(0) should return error")
+	}
+
+	seeded := NewSeededRand([32]byte{1})
+	for i := 0; i < 1000; i++ {
+		v, err := seeded.IntN(7)
+		if err != nil || v < 0 || v >= 7 {
+			t.Fatalf("IntN(7) = %d, %v", v, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package randomanimals
+
+import (
+	"testing"
+
+	"github.com/example/hashpassword/randtest"
+)
+
+func TestGetRandomAnimalsStatistics(t *testing.T) {
+	// Without replacement every position is still uniform over all animals.
+	// Animals within one draw are dependent, so the sequence tests only look
+	// at the first animal of consecutive draws.
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(2000, func() ([]string, error) {
+			return GetRandomAnimals(3)
+		})
+		if err != nil {
+			return nil, err
+		}
+		results, err := randtest.PositionTests("GetRandomAnimals", samples, animals)
+		if err != nil {
+			return nil, err
+		}
+		firsts := make([][]string, len(samples))
+		for i, sample := range samples {
+			firsts[i] = sample[:1]
+		}
+		sequence, err := randtest.SequenceTests("GetRandomAnimals", firsts, animals)
+		if err != nil {
+			return nil, err
+		}
+		return append(results, sequence...), nil
+	})
+}
+
+func TestWeightedRandomStatistics(t *testing.T) {
+	c, err := NewCatalog([]Animal{
+		{ID: "ant", Name: "Ant", Weight: 1},
+		{ID: "bee", Name: "Bee", Weight: 2},
+		{ID: "cat", Name: "Cat", Weight: 3},
+		{ID: "dog", Name: "Dog", Weight: 4},
+	})
+	if err != nil {
+		t.Fatalf("NewCatalog() returned error: %v", err)
+	}
+	expected := []float64{0.1, 0.2, 0.3, 0.4}
+
+	randtest.Run(t, randtest.Config{}, func() ([]randtest.Result, error) {
+		samples, err := randtest.Collect(4000, func() ([]string, error) {
+			a, err := c.WeightedRandom()
+			return []string{a.Name}, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		counts, err := randtest.Counts(samples, c.Names())
+		if err != nil {
+			return nil, err
+		}
+		return []randtest.Result{randtest.ChiSquaredTest("WeightedRandom", counts, expected)}, nil
+	})
+}
@@ -0,0 +1,67 @@
+package randtest
+
+import "math"
+
+const (
+	gammaEpsilon       = 1e-14
+	gammaMaxIterations = 1000
+)
+
+// chiSquaredSurvival returns P(X >= x) for a chi-squared variable with df
+// degrees of freedom, i.e. the p-value of a chi-squared statistic
+func chiSquaredSurvival(x float64, df int) float64 {
+	if df <= 0 {
+		return 1
+	}
+	if x <= 0 {
+		return 1
+	}
+	return upperGammaRegularized(float64(df)/2, x/2)
+}
+
+// upperGammaRegularized computes Q(a, x) = Γ(a, x) / Γ(a), using the power
+// series for x < a+1 and a continued fraction otherwise
+func upperGammaRegularized(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		// P(a, x) = x^a e^-x / Γ(a) * Σ x^n / (a (a+1) ... (a+n))
+		sum := 1 / a
+		term := sum
+		for n := 1; n < gammaMaxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*gammaEpsilon {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Lentz's method for the continued fraction of Q(a, x)
+	tiny := 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < gammaMaxIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < gammaEpsilon {
+			break
+		}
+	}
+	return prefix * h
+}
@@ -0,0 +1,349 @@
+// Package randtest is a small statistical test harness for random generators:
+// chi-squared goodness of fit, the Wald-Wolfowitz runs test, lag-1 serial
+// correlation and per-position symbol distributions. Generators are sampled
+// into sequences of symbols (runes of a password, names of animals) so one
+// harness serves every generator in the module.
+package randtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"testing"
+)
+
+const (
+	// DefaultAlpha is the significance level below which a result fails
+	DefaultAlpha = 1e-4
+	// DefaultAttempts is how many independent samples must all fail before
+	// a check is reported, so a correct generator flakes with probability
+	// of roughly (number of results * alpha) ^ attempts
+	DefaultAttempts = 2
+	// reportEnv enables p-value reporting when set to a non-empty value
+	reportEnv = "RANDTEST_REPORT"
+)
+
+// Result is the outcome of one statistical test
+type Result struct {
+	Name      string
+	Statistic float64
+	// DF is the degrees of freedom for chi-squared results, 0 otherwise
+	DF     int
+	PValue float64
+}
+
+// Pass reports whether the result is consistent with randomness at level alpha
+func (r Result) Pass(alpha float64) bool {
+	return r.PValue >= alpha
+}
+
+func (r Result) String() string {
+	if r.DF > 0 {
+		return fmt.Sprintf("%s: chi2=%.3f df=%d p=%.6f", r.Name, r.Statistic, r.DF, r.PValue)
+	}
+	return fmt.Sprintf("%s: z=%.3f p=%.6f", r.Name, r.Statistic, r.PValue)
+}
+
+// Config controls Run
+type Config struct {
+	// Alpha is the significance level; 0 means DefaultAlpha
+	Alpha float64
+	// Attempts is the number of independent samples; 0 means DefaultAttempts
+	Attempts int
+	// Report logs every p-value. It is also enabled by go test -v or by
+	// setting RANDTEST_REPORT=1.
+	Report bool
+}
+
+// Run calls sample to draw fresh data and compute results, failing tb only
+// if some result is below alpha in every one of the configured attempts
+func Run(tb testing.TB, cfg Config, sample func() ([]Result, error)) {
+	tb.Helper()
+	alpha := cfg.Alpha
+	if alpha == 0 {
+		alpha = DefaultAlpha
+	}
+	attempts := cfg.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+	report := cfg.Report || testing.Verbose() || os.Getenv(reportEnv) != ""
+
+	var failed []Result
+	for attempt := 1; attempt <= attempts; attempt++ {
+		results, err := sample()
+		if err != nil {
+			tb.Fatalf("sampling failed: %v", err)
+		}
+
+		failed = failed[:0]
+		for _, r := range results {
+			if report {
+				tb.Logf("attempt %d: %s", attempt, r)
+			}
+			if !r.Pass(alpha) {
+				failed = append(failed, r)
+			}
+		}
+		if len(failed) == 0 {
+			return
+		}
+	}
+
+	for _, r := range failed {
+		tb.Errorf("looks non-random in all %d attempts (alpha=%g): %s", attempts, alpha, r)
+	}
+}
+
+// Report writes one line per result, for use outside of tests
+func Report(w io.Writer, results []Result) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintln(w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runes splits s into single-rune symbols
+func Runes(s string) []string {
+	symbols := make([]string, 0, len(s))
+	for _, r := range s {
+		symbols = append(symbols, string(r))
+	}
+	return symbols
+}
+
+// Collect calls gen n times and returns the samples
+func Collect(n int, gen func() ([]string, error)) ([][]string, error) {
+	samples := make([][]string, n)
+	for i := range samples {
+		sample, err := gen()
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = sample
+	}
+	return samples, nil
+}
+
+// indexOf maps every symbol to its position in symbols
+func indexOf(symbols []string) (map[string]int, error) {
+	index := make(map[string]int, len(symbols))
+	for i, s := range symbols {
+		if _, dup := index[s]; dup {
+			return nil, fmt.Errorf("duplicate symbol %q", s)
+		}
+		index[s] = i
+	}
+	return index, nil
+}
+
+// Counts returns how often each of symbols occurs across all samples.
+// Symbols outside the list are an error.
+func Counts(samples [][]string, symbols []string) ([]int, error) {
+	index, err := indexOf(symbols)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int, len(symbols))
+	for _, sample := range samples {
+		for _, s := range sample {
+			i, ok := index[s]
+			if !ok {
+				return nil, fmt.Errorf("unexpected symbol %q", s)
+			}
+			counts[i]++
+		}
+	}
+	return counts, nil
+}
+
+// PositionCounts returns counts[position][symbol] over equally long samples
+func PositionCounts(samples [][]string, symbols []string) ([][]int, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples")
+	}
+	index, err := indexOf(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	length := len(samples[0])
+	counts := make([][]int, length)
+	for p := range counts {
+		counts[p] = make([]int, len(symbols))
+	}
+	for _, sample := range samples {
+		if len(sample) != length {
+			return nil, fmt.Errorf("sample length %d differs from %d", len(sample), length)
+		}
+		for p, s := range sample {
+			i, ok := index[s]
+			if !ok {
+				return nil, fmt.Errorf("unexpected symbol %q", s)
+			}
+			counts[p][i]++
+		}
+	}
+	return counts, nil
+}
+
+// Indices flattens samples into the sequence of symbol indexes, the input
+// for RunsTest and SerialCorrelationTest
+func Indices(samples [][]string, symbols []string) ([]float64, error) {
+	index, err := indexOf(symbols)
+	if err != nil {
+		return nil, err
+	}
+	var values []float64
+	for _, sample := range samples {
+		for _, s := range sample {
+			i, ok := index[s]
+			if !ok {
+				return nil, fmt.Errorf("unexpected symbol %q", s)
+			}
+			values = append(values, float64(i))
+		}
+	}
+	return values, nil
+}
+
+// ChiSquaredTest compares observed counts with expected probabilities
+// (which must sum to 1) using Pearson's chi-squared statistic
+func ChiSquaredTest(name string, observed []int, expected []float64) Result {
+	total := 0
+	for _, o := range observed {
+		total += o
+	}
+
+	stat := 0.0
+	for i, o := range observed {
+		e := expected[i] * float64(total)
+		if e > 0 {
+			d := float64(o) - e
+			stat += d * d / e
+		}
+	}
+	df := len(observed) - 1
+	return Result{Name: name, Statistic: stat, DF: df, PValue: chiSquaredSurvival(stat, df)}
+}
+
+// UniformityTest is ChiSquaredTest against the uniform distribution
+func UniformityTest(name string, observed []int) Result {
+	expected := make([]float64, len(observed))
+	for i := range expected {
+		expected[i] = 1 / float64(len(observed))
+	}
+	return ChiSquaredTest(name, observed, expected)
+}
+
+// PositionTests runs UniformityTest on the symbol distribution of every
+// position of equally long samples
+func PositionTests(name string, samples [][]string, symbols []string) ([]Result, error) {
+	counts, err := PositionCounts(samples, symbols)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(counts))
+	for p, c := range counts {
+		results[p] = UniformityTest(fmt.Sprintf("%s position %d", name, p), c)
+	}
+	return results, nil
+}
+
+// RunsTest is the Wald-Wolfowitz runs test above and below the median.
+// Too few runs indicate clustering, too many indicate alternation.
+func RunsTest(name string, values []float64) Result {
+	median := medianOf(values)
+
+	runs, above, below := 0, 0, 0
+	prev := 0
+	for _, v := range values {
+		side := 0
+		switch {
+		case v > median:
+			side = 1
+			above++
+		case v < median:
+			side = -1
+			below++
+		default:
+			// Values equal to the median carry no information
+			continue
+		}
+		if side != prev {
+			runs++
+			prev = side
+		}
+	}
+
+	n := float64(above + below)
+	if above == 0 || below == 0 {
+		return Result{Name: name + " runs", PValue: 0}
+	}
+	n1, n2 := float64(above), float64(below)
+	mean := 2*n1*n2/n + 1
+	variance := 2 * n1 * n2 * (2*n1*n2 - n) / (n * n * (n - 1))
+	z := (float64(runs) - mean) / math.Sqrt(variance)
+	return Result{Name: name + " runs", Statistic: z, PValue: math.Erfc(math.Abs(z) / math.Sqrt2)}
+}
+
+// SerialCorrelationTest tests the lag-1 autocorrelation of values, which is
+// approximately normal with mean -1/n and variance 1/n for independent values
+func SerialCorrelationTest(name string, values []float64) Result {
+	n := float64(len(values))
+	if len(values) < 3 {
+		return Result{Name: name + " serial correlation", PValue: 0}
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= n
+
+	num, den := 0.0, 0.0
+	for i, v := range values {
+		d := v - mean
+		den += d * d
+		if i+1 < len(values) {
+			num += d * (values[i+1] - mean)
+		}
+	}
+	if den == 0 {
+		return Result{Name: name + " serial correlation", PValue: 0}
+	}
+
+	r := num / den
+	z := (r + 1/n) * math.Sqrt(n)
+	return Result{Name: name + " serial correlation", Statistic: z, PValue: math.Erfc(math.Abs(z) / math.Sqrt2)}
+}
+
+// SequenceTests runs the runs test and serial correlation test over the
+// flattened symbol indexes of samples
+func SequenceTests(name string, samples [][]string, symbols []string) ([]Result, error) {
+	values, err := Indices(samples, symbols)
+	if err != nil {
+		return nil, err
+	}
+	return []Result{RunsTest(name, values), SerialCorrelationTest(name, values)}, nil
+}
+
+// medianOf returns the median without modifying values
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
@@ -0,0 +1,172 @@
+package randtest
+
+import (
+	"bytes"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestChiSquaredSurvival(t *testing.T) {
+	// Reference values from standard chi-squared tables
+	tests := []struct {
+		x    float64
+		df   int
+		want float64
+	}{
+		{x: 3.841459, df: 1, want: 0.05},
+		{x: 6.634897, df: 1, want: 0.01},
+		{x: 18.307038, df: 10, want: 0.05},
+		{x: 2.155856, df: 10, want: 0.995},
+		{x: 124.342113, df: 100, want: 0.05},
+		{x: 0, df: 5, want: 1},
+	}
+
+	for _, tt := range tests {
+		got := chiSquaredSurvival(tt.x, tt.df)
+		if math.Abs(got-tt.want) > 1e-5 {
+			t.Errorf("chiSquaredSurvival(%v, %d) = %.6f, want %.6f", tt.x, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestUniformityTest(t *testing.T) {
+	fair := UniformityTest("fair", []int{100, 98, 103, 99})
+	if !fair.Pass(DefaultAlpha) || fair.DF != 3 {
+		t.Errorf("UniformityTest(fair) = %s, want pass with 3 df", fair)
+	}
+
+	biased := UniformityTest("biased", []int{400, 100, 100, 100})
+	if biased.Pass(DefaultAlpha) {
+		t.Errorf("UniformityTest(biased) = %s, want fail", biased)
+	}
+}
+
+func TestRunsTest(t *testing.T) {
+	alternating := make([]float64, 1000)
+	clustered := make([]float64, 1000)
+	for i := range alternating {
+		alternating[i] = float64(i % 2)
+		clustered[i] = float64(i / 500)
+	}
+	if r := RunsTest("alternating", alternating); r.Pass(DefaultAlpha) {
+		t.Errorf("RunsTest(alternating) = %s, want fail", r)
+	}
+	if r := RunsTest("clustered", clustered); r.Pass(DefaultAlpha) {
+		t.Errorf("RunsTest(clustered) = %s, want fail", r)
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := make([]float64, 1000)
+	for i := range random {
+		random[i] = rng.Float64()
+	}
+	if r := RunsTest("random", random); !r.Pass(DefaultAlpha) {
+		t.Errorf("RunsTest(random) = %s, want pass", r)
+	}
+}
+
+func TestSerialCorrelationTest(t *testing.T) {
+	increasing := make([]float64, 1000)
+	for i := range increasing {
+		increasing[i] = float64(i % 100)
+	}
+	if r := SerialCorrelationTest("increasing", increasing); r.Pass(DefaultAlpha) {
+		t.Errorf("SerialCorrelationTest(increasing) = %s, want fail", r)
+	}
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	random := make([]float64, 1000)
+	for i := range random {
+		random[i] = float64(rng.IntN(10))
+	}
+	if r := SerialCorrelationTest("random", random); !r.Pass(DefaultAlpha) {
+		t.Errorf("SerialCorrelationTest(random) = %s, want pass", r)
+	}
+}
+
+func TestPositionTests(t *testing.T) {
+	symbols := []string{"a", "b", "c"}
+
+	// Position 0 is always "a", position 1 cycles fairly
+	var samples [][]string
+	for i := 0; i < 300; i++ {
+		samples = append(samples, []string{"a", symbols[i%3]})
+	}
+	results, err := PositionTests("stuck", samples, symbols)
+	if err != nil {
+		t.Fatalf("PositionTests() returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("PositionTests() returned %d results, want 2", len(results))
+	}
+	if results[0].Pass(DefaultAlpha) {
+		t.Errorf("position 0 = %s, want fail", results[0])
+	}
+	if !results[1].Pass(DefaultAlpha) {
+		t.Errorf("position 1 = %s, want pass", results[1])
+	}
+
+	if _, err := PositionTests("bad", [][]string{{"a"}, {"a", "b"}}, symbols); err == nil {
+		t.Error("PositionTests() with unequal lengths should return error")
+	}
+	if _, err := PositionTests("bad", [][]string{{"z"}}, symbols); err == nil {
+		t.Error("PositionTests() with unknown symbol should return error")
+	}
+	if _, err := Counts(nil, []string{"a", "a"}); err == nil {
+		t.Error("Counts() with duplicate symbols should return error")
+	}
+}
+
+// recorder captures Run's verdict without failing the real test
+type recorder struct {
+	testing.TB
+	errors int
+	logs   int
+}
+
+func (r *recorder) Helper()                       {}
+func (r *recorder) Errorf(string, ...interface{}) { r.errors++ }
+func (r *recorder) Logf(string, ...interface{})   { r.logs++ }
+
+func TestRun(t *testing.T) {
+	calls := 0
+	flaky := func() ([]Result, error) {
+		calls++
+		// Fails the first attempt only, like an unlucky correct generator
+		if calls == 1 {
+			return []Result{{Name: "unlucky", PValue: 1e-6}}, nil
+		}
+		return []Result{{Name: "fine", PValue: 0.5}}, nil
+	}
+	rec := &recorder{TB: t}
+	Run(rec, Config{Report: true}, flaky)
+	if rec.errors != 0 || calls != 2 || rec.logs != 2 {
+		t.Errorf("Run(flaky) errors=%d calls=%d logs=%d, want 0, 2, 2", rec.errors, calls, rec.logs)
+	}
+
+	broken := func() ([]Result, error) {
+		return []Result{{Name: "broken", PValue: 0}, {Name: "fine", PValue: 0.5}}, nil
+	}
+	rec = &recorder{TB: t}
+	Run(rec, Config{Attempts: 3}, broken)
+	if rec.errors != 1 {
+		t.Errorf("Run(broken) reported %d errors, want 1", rec.errors)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	results := []Result{
+		UniformityTest("letters", []int{10, 10, 10}),
+		{Name: "sequence runs", Statistic: 1.5, PValue: 0.133614},
+	}
+	if err := Report(&buf, results); err != nil {
+		t.Fatalf("Report() returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "letters: chi2=0.000 df=2 p=1.000000") || !strings.Contains(out, "sequence runs: z=1.500 p=0.133614") {
+		t.Errorf("Report() = %q", out)
+	}
+}
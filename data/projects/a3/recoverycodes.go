@@ -0,0 +1,161 @@
+package hashpassword
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/example/hashpassword/internal/cryptorand"
+)
+
+// crockfordAlphabet is Crockford's base32 alphabet, which leaves out the
+// easily confused letters I, L, O and U
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	// DefaultRecoveryCodeCount is the number of codes in a default set
+	DefaultRecoveryCodeCount = 10
+	// DefaultRecoveryCodeGroups is the number of hyphen separated groups per code
+	DefaultRecoveryCodeGroups = 2
+	// DefaultRecoveryCodeGroupSize is the number of characters per group
+	DefaultRecoveryCodeGroupSize = 4
+	// recoveryCodeSeparator separates the groups of a displayed code
+	recoveryCodeSeparator = "-"
+)
+
+var (
+	// ErrRecoveryCodeNotFound is returned when a code matches no unused stored hash
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found or already used")
+	// ErrInvalidRecoveryCode is returned when a code contains characters outside the alphabet
+	ErrInvalidRecoveryCode = errors.New("invalid recovery code")
+)
+
+// RecoveryCodes is a freshly generated set of single-use recovery codes.
+// Plaintext is shown to the user once; only Hashes should be stored.
+// Plaintext[i] corresponds to Hashes[i].
+type RecoveryCodes struct {
+	Plaintext []string
+	Hashes    []string
+}
+
+// GenerateRecoveryCodes generates count codes in the XXXX-XXXX format and
+// hashes them with HashPassword using secretKey
+func GenerateRecoveryCodes(count int, secretKey string) (*RecoveryCodes, error) {
+	return GenerateRecoveryCodesWithFormat(count, DefaultRecoveryCodeGroups, DefaultRecoveryCodeGroupSize, secretKey)
+}
+
+// GenerateRecoveryCodesWithFormat generates count unique codes made of groups
+// of groupSize Crockford base32 characters separated by hyphens
+func GenerateRecoveryCodesWithFormat(count, groups, groupSize int, secretKey string) (*RecoveryCodes, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+	if groups <= 0 || groupSize <= 0 {
+		return nil, errors.New("groups and group size must be greater than 0")
+	}
+	if secretKey == "" {
+		return nil, ErrEmptySecretKey
+	}
+	// Each character carries 5 bits; below 40 bits codes become guessable
+	if groups*groupSize*5 < 40 {
+		return nil, errors.New("recovery codes must have at least 8 characters")
+	}
+
+	codes := &RecoveryCodes{
+		Plaintext: make([]string, 0, count),
+		Hashes:    make([]string, 0, count),
+	}
+	seen := make(map[string]bool, count)
+
+	for len(codes.Plaintext) < count {
+		code, err := generateRecoveryCode(groups, groupSize)
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		hash, err := HashPassword(NormalizeRecoveryCode(code), secretKey)
+		if err != nil {
+			return nil, err
+		}
+		codes.Plaintext = append(codes.Plaintext, code)
+		codes.Hashes = append(codes.Hashes, hash)
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode draws one code using crypto/rand
+func generateRecoveryCode(groups, groupSize int) (string, error) {
+	var code strings.Builder
+	for g := 0; g < groups; g++ {
+		if g > 0 {
+			code.WriteString(recoveryCodeSeparator)
+		}
+		for i := 0; i < groupSize; i++ {
+			idx, err := cryptorand.Index(len(crockfordAlphabet))
+			if err != nil {
+				return "", err
+			}
+			code.WriteByte(crockfordAlphabet[idx])
+		}
+	}
+	return code.String(), nil
+}
+
+// NormalizeRecoveryCode converts user input to the canonical form that is
+// hashed: separators and spaces removed, upper case, and the Crockford
+// aliases I/L mapped to 1 and O to 0. It returns "" if the input contains
+// characters outside the alphabet.
+func NormalizeRecoveryCode(code string) string {
+	var normalized strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'I' || r == 'L':
+			r = '1'
+		case r == 'O':
+			r = '0'
+		case !strings.ContainsRune(crockfordAlphabet, r):
+			return ""
+		}
+		normalized.WriteRune(r)
+	}
+	return normalized.String()
+}
+
+// ConsumeRecoveryCode verifies code against the stored hashes and returns the
+// index of the matching entry. The matching entry is cleared in hashes so the
+// code cannot be used again; callers must persist the updated slice.
+// Cleared (empty) entries are skipped.
+func ConsumeRecoveryCode(code string, hashes []string, secretKey string) (int, error) {
+	normalized := NormalizeRecoveryCode(code)
+	if normalized == "" {
+		return -1, ErrInvalidRecoveryCode
+	}
+
+	// Check every entry so the response time does not reveal the index
+	match := -1
+	for i, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+		ok, err := VerifyPassword(normalized, hash, secretKey)
+		if err != nil {
+			return -1, fmt.Errorf("recovery code hash %d: %w", i, err)
+		}
+		if ok && match < 0 {
+			match = i
+		}
+	}
+
+	if match < 0 {
+		return -1, ErrRecoveryCodeNotFound
+	}
+	hashes[match] = ""
+	return match, nil
+}
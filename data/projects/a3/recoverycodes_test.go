@@ -0,0 +1,138 @@
+package hashpassword
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+
+	codes, err := GenerateRecoveryCodes(DefaultRecoveryCodeCount, secretKey)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() unexpected error = %v", err)
+	}
+	if len(codes.Plaintext) != DefaultRecoveryCodeCount || len(codes.Hashes) != DefaultRecoveryCodeCount {
+		t.Fatalf("GenerateRecoveryCodes() returned %d codes and %d hashes, want %d",
+			len(codes.Plaintext), len(codes.Hashes), DefaultRecoveryCodeCount)
+	}
+
+	format := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}-[0-9A-HJKMNP-TV-Z]{4}$`)
+	seen := make(map[string]bool)
+	for i, code := range codes.Plaintext {
+		if !format.MatchString(code) {
+			t.Errorf("GenerateRecoveryCodes() code %q does not match XXXX-XXXX Crockford format", code)
+		}
+		if seen[code] {
+			t.Errorf("GenerateRecoveryCodes() returned duplicate code %q", code)
+		}
+		seen[code] = true
+
+		if strings.Contains(codes.Hashes[i], code) {
+			t.Errorf("GenerateRecoveryCodes() hash %d contains the plaintext", i)
+		}
+		ok, err := VerifyPassword(NormalizeRecoveryCode(code), codes.Hashes[i], secretKey)
+		if err != nil || !ok {
+			t.Errorf("VerifyPassword() for code %d = %v, %v, want true", i, ok, err)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodesWithFormat(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+
+	codes, err := GenerateRecoveryCodesWithFormat(3, 3, 5, secretKey)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodesWithFormat() unexpected error = %v", err)
+	}
+	for _, code := range codes.Plaintext {
+		groups := strings.Split(code, "-")
+		if len(groups) != 3 || len(groups[0]) != 5 {
+			t.Errorf("GenerateRecoveryCodesWithFormat() code %q, want 3 groups of 5", code)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		count     int
+		groups    int
+		groupSize int
+		secretKey string
+	}{
+		{name: "Zero count", count: 0, groups: 2, groupSize: 4, secretKey: secretKey},
+		{name: "Zero groups", count: 1, groups: 0, groupSize: 4, secretKey: secretKey},
+		{name: "Too short", count: 1, groups: 1, groupSize: 6, secretKey: secretKey},
+		{name: "Empty secret key", count: 1, groups: 2, groupSize: 4, secretKey: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateRecoveryCodesWithFormat(tt.count, tt.groups, tt.groupSize, tt.secretKey); err == nil {
+				t.Error("GenerateRecoveryCodesWithFormat() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "ABCD-EFGH", want: "ABCDEFGH"},
+		{input: "abcd efgh", want: "ABCDEFGH"},
+		{input: "0IL1-OOXY", want: "011100XY"},
+		{input: "ABCD-EFGU", want: ""},
+		{input: "ABCD!EFGH", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeRecoveryCode(tt.input); got != tt.want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConsumeRecoveryCode(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+
+	codes, err := GenerateRecoveryCodes(5, secretKey)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() unexpected error = %v", err)
+	}
+	stored := append([]string(nil), codes.Hashes...)
+
+	// Users may type the code in lower case without the hyphen
+	input := strings.ToLower(strings.ReplaceAll(codes.Plaintext[3], "-", ""))
+	index, err := ConsumeRecoveryCode(input, stored, secretKey)
+	if err != nil {
+		t.Fatalf("ConsumeRecoveryCode() unexpected error = %v", err)
+	}
+	if index != 3 {
+		t.Errorf("ConsumeRecoveryCode() index = %d, want 3", index)
+	}
+	if stored[3] != "" {
+		t.Error("ConsumeRecoveryCode() did not clear the used hash")
+	}
+
+	// A consumed code cannot be reused
+	if _, err := ConsumeRecoveryCode(codes.Plaintext[3], stored, secretKey); !errors.Is(err, ErrRecoveryCodeNotFound) {
+		t.Errorf("ConsumeRecoveryCode() reuse error = %v, want ErrRecoveryCodeNotFound", err)
+	}
+
+	// Other codes still work
+	if index, err := ConsumeRecoveryCode(codes.Plaintext[0], stored, secretKey); err != nil || index != 0 {
+		t.Errorf("ConsumeRecoveryCode() = %d, %v, want 0, nil", index, err)
+	}
+
+	if _, err := ConsumeRecoveryCode("ZZZZ-ZZZZ", stored, "wrong-secret-key"); !errors.Is(err, ErrRecoveryCodeNotFound) {
+		t.Errorf("ConsumeRecoveryCode() with wrong key error = %v, want ErrRecoveryCodeNotFound", err)
+	}
+	if _, err := ConsumeRecoveryCode("not a code!", stored, secretKey); !errors.Is(err, ErrInvalidRecoveryCode) {
+		t.Errorf("ConsumeRecoveryCode() error = %v, want ErrInvalidRecoveryCode", err)
+	}
+	if _, err := ConsumeRecoveryCode(codes.Plaintext[1], []string{"garbage"}, secretKey); err == nil {
+		t.Error("ConsumeRecoveryCode() expected error for malformed stored hash")
+	}
+}
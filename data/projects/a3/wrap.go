@@ -0,0 +1,144 @@
+package hashpassword
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// WrappedAlgorithmIdentifier is the prefix of hmac_sha256 hashes that
+	// have been wrapped in PBKDF2
+	WrappedAlgorithmIdentifier = "wrap(pbkdf2," + AlgorithmIdentifier + ")"
+	// DefaultWrapIterations is the PBKDF2-HMAC-SHA256 iteration count for
+	// wrapping (OWASP's 2023 recommendation)
+	DefaultWrapIterations = 600_000
+	// MinWrapIterations is the lowest iteration count accepted
+	MinWrapIterations = 1_000
+	// MaxWrapIterations is the highest iteration count accepted, so that a
+	// tampered stored hash cannot make every verification take minutes
+	MaxWrapIterations = 10_000_000
+	// wrapSaltLength is the length of the PBKDF2 salt in bytes
+	wrapSaltLength = 16
+	// wrapIterationsPrefix introduces the iteration count field
+	wrapIterationsPrefix = "i="
+)
+
+// ErrWrapIterations is returned for an iteration count outside
+// [MinWrapIterations, MaxWrapIterations]
+var ErrWrapIterations = fmt.Errorf("iterations must be between %d and %d", MinWrapIterations, MaxWrapIterations)
+
+// WrappedHash is an hmac_sha256 hash whose digest has been fed through
+// PBKDF2-HMAC-SHA256, so stored hashes can be strengthened without the
+// passwords. Its serialized form is
+//
+//	wrap(pbkdf2,hmac_sha256)$i=iterations$salt$hmac_salt$hash
+//
+// where salt is the PBKDF2 salt, hmac_salt the salt of the original hash and
+// hash PBKDF2(HMAC-SHA256(secret key, password || hmac_salt), salt), all
+// base64url encoded.
+type WrappedHash struct {
+	Iterations int
+	Salt       []byte
+	HMACSalt   []byte
+	Digest     []byte
+}
+
+// String returns the hash in its serialized form
+func (w *WrappedHash) String() string {
+	return strings.Join([]string{
+		WrappedAlgorithmIdentifier,
+		wrapIterationsPrefix + strconv.Itoa(w.Iterations),
+		base64.URLEncoding.EncodeToString(w.Salt),
+		base64.URLEncoding.EncodeToString(w.HMACSalt),
+		base64.URLEncoding.EncodeToString(w.Digest),
+	}, Separator)
+}
+
+// NeedsRehash reports whether the hash should be replaced the next time the
+// password is known, because it has fewer than DefaultWrapIterations or its
+// original salt is shorter than DefaultSaltLength
+func (w *WrappedHash) NeedsRehash() bool {
+	return w.Iterations < DefaultWrapIterations || len(w.HMACSalt) < DefaultSaltLength
+}
+
+// verify recomputes the inner HMAC of password, feeds it through PBKDF2 and
+// compares the result with the digest in constant time
+func (w *WrappedHash) verify(password, secretKey string) bool {
+	inner := computeHMAC(password, secretKey, w.HMACSalt)
+	computed := pbkdf2SHA256(inner, w.Salt, w.Iterations, sha256.Size)
+	return subtle.ConstantTimeCompare(computed, w.Digest) == 1
+}
+
+// Wrap wraps the hash in PBKDF2 with the given number of iterations and a
+// random salt. The password and secret key are not needed.
+func (h *PasswordHash) Wrap(iterations int) (*WrappedHash, error) {
+	if iterations < MinWrapIterations || iterations > MaxWrapIterations {
+		return nil, ErrWrapIterations
+	}
+	salt := make([]byte, wrapSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return &WrappedHash{
+		Iterations: iterations,
+		Salt:       salt,
+		HMACSalt:   h.Salt,
+		Digest:     pbkdf2SHA256(h.Digest, salt, iterations, sha256.Size),
+	}, nil
+}
+
+// WrapHash wraps a hash produced by HashPassword in PBKDF2 with the given
+// number of iterations, returning the serialized WrappedHash
+func WrapHash(hash string, iterations int) (string, error) {
+	parsed, err := ParseHash(hash)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := parsed.Wrap(iterations)
+	if err != nil {
+		return "", err
+	}
+	return wrapped.String(), nil
+}
+
+// ParseWrappedHash parses a hash produced by WrapHash without verifying it.
+// It returns ErrInvalidAlgorithm for another algorithm and an error wrapping
+// ErrInvalidHash for anything else that is not a well-formed wrapped hash.
+func ParseWrappedHash(hash string) (*WrappedHash, error) {
+	parts := strings.Split(hash, Separator)
+	if parts[0] != WrappedAlgorithmIdentifier {
+		return nil, ErrInvalidAlgorithm
+	}
+	if len(parts) != 5 {
+		return nil, ErrInvalidHash
+	}
+
+	count, found := strings.CutPrefix(parts[1], wrapIterationsPrefix)
+	iterations, err := strconv.Atoi(count)
+	if !found || err != nil {
+		return nil, fmt.Errorf("%w: invalid iterations %q", ErrInvalidHash, parts[1])
+	}
+	if iterations < MinWrapIterations || iterations > MaxWrapIterations {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHash, ErrWrapIterations)
+	}
+
+	fields := make([][]byte, 3)
+	for i, name := range []string{"salt", "hmac salt", "hash"} {
+		if fields[i], err = base64.URLEncoding.DecodeString(parts[i+2]); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s: %w", ErrInvalidHash, name, err)
+		}
+		if len(fields[i]) == 0 {
+			return nil, fmt.Errorf("%w: empty %s", ErrInvalidHash, name)
+		}
+	}
+	if len(fields[2]) != sha256.Size {
+		return nil, fmt.Errorf("%w: hash is %d bytes, want %d", ErrInvalidHash, len(fields[2]), sha256.Size)
+	}
+
+	return &WrappedHash{Iterations: iterations, Salt: fields[0], HMACSalt: fields[1], Digest: fields[2]}, nil
+}
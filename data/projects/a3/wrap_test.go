@@ -0,0 +1,132 @@
+package hashpassword
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWrapHash(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	hash, _ := HashPassword("password", secretKey)
+
+	wrapped, err := WrapHash(hash, MinWrapIterations)
+	if err != nil {
+		t.Fatalf("WrapHash returned error: %v", err)
+	}
+	if !strings.HasPrefix(wrapped, "wrap(pbkdf2,hmac_sha256)$i=1000$") {
+		t.Errorf("WrapHash = %s, want the wrap(pbkdf2,hmac_sha256)$i=1000$ prefix", wrapped)
+	}
+
+	parsed, err := ParseWrappedHash(wrapped)
+	if err != nil {
+		t.Fatalf("ParseWrappedHash returned error: %v", err)
+	}
+	if parsed.String() != wrapped {
+		t.Errorf("String() = %s, want %s", parsed.String(), wrapped)
+	}
+
+	// The digest is PBKDF2 over the original HMAC, recomputable from the password
+	inner, _ := ParseHash(hash)
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte("password"))
+	mac.Write(parsed.HMACSalt)
+	if !hmac.Equal(mac.Sum(nil), inner.Digest) {
+		t.Fatal("HMACSalt does not reproduce the original digest")
+	}
+	if want := pbkdf2SHA256(inner.Digest, parsed.Salt, parsed.Iterations, sha256.Size); !hmac.Equal(parsed.Digest, want) {
+		t.Error("wrapped digest is not PBKDF2 of the original digest")
+	}
+
+	// Each wrap uses a new salt
+	if again, _ := WrapHash(hash, MinWrapIterations); again == wrapped {
+		t.Error("wrapping twice gave the same hash")
+	}
+}
+
+func TestWrapHashErrors(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	hash, _ := HashPassword("password", secretKey)
+
+	if _, err := WrapHash(hash, MinWrapIterations-1); !errors.Is(err, ErrWrapIterations) {
+		t.Errorf("WrapHash with too few iterations error = %v, want ErrWrapIterations", err)
+	}
+	if _, err := WrapHash(hash, MaxWrapIterations+1); !errors.Is(err, ErrWrapIterations) {
+		t.Errorf("WrapHash with too many iterations error = %v, want ErrWrapIterations", err)
+	}
+	if _, err := WrapHash("invalid", MinWrapIterations); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("WrapHash(invalid) error = %v, want ErrInvalidHash", err)
+	}
+
+	wrapped, _ := WrapHash(hash, MinWrapIterations)
+	parts := strings.Split(wrapped, Separator)
+	invalid := []string{
+		strings.Join(parts[:4], Separator),
+		strings.Replace(wrapped, "i=1000", "i=999", 1),
+		strings.Replace(wrapped, "i=1000", "i=2000000000", 1),
+		strings.Replace(wrapped, "i=1000", "n=1000", 1),
+		strings.Join(append(parts[:2:2], "!!", parts[3], parts[4]), Separator),
+		strings.Join(append(parts[:3:3], "", parts[4]), Separator),
+		strings.Join(append(parts[:4:4], "QUFB"), Separator),
+	}
+	for _, h := range invalid {
+		if _, err := ParseWrappedHash(h); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("ParseWrappedHash(%q) error = %v, want ErrInvalidHash", h, err)
+		}
+	}
+	if _, err := ParseWrappedHash(hash); err != ErrInvalidAlgorithm {
+		t.Errorf("ParseWrappedHash of an unwrapped hash error = %v, want ErrInvalidAlgorithm", err)
+	}
+}
+
+func TestVerifyWrappedPassword(t *testing.T) {
+	secretKey := "test-secret-key-at-least-32-bytes-long!"
+	hash, _ := HashPasswordWithSaltLength("password", secretKey, 16)
+	wrapped, _ := WrapHash(hash, MinWrapIterations)
+
+	tests := []struct {
+		name      string
+		password  string
+		secretKey string
+		want      bool
+	}{
+		{"correct password", "password", secretKey, true},
+		{"wrong password", "wrong", secretKey, false},
+		{"wrong secret key", "password", "another-secret-key", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPassword(tt.password, wrapped, tt.secretKey)
+			if err != nil || got != tt.want {
+				t.Errorf("VerifyPassword = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	// The iteration count is part of what is verified
+	if ok, _ := VerifyPassword("password", strings.Replace(wrapped, "i=1000", "i=1001", 1), secretKey); ok {
+		t.Error("VerifyPassword ignored the iteration count")
+	}
+	// A tampered iteration count is rejected rather than computed
+	if _, err := VerifyPassword("password", strings.Replace(wrapped, "i=1000", "i=2000000000", 1), secretKey); !errors.Is(err, ErrWrapIterations) {
+		t.Errorf("VerifyPassword with 2e9 iterations error = %v, want ErrWrapIterations", err)
+	}
+	if _, err := VerifyPassword("password", WrappedAlgorithmIdentifier+"$i=1000$abc", secretKey); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("VerifyPassword of a truncated wrapped hash error = %v, want ErrInvalidHash", err)
+	}
+
+	parsed, _ := ParseWrappedHash(wrapped)
+	if !parsed.NeedsRehash() {
+		t.Error("NeedsRehash() = false for 1000 iterations and a 16-byte salt")
+	}
+	parsed.Iterations, parsed.HMACSalt = DefaultWrapIterations, make([]byte, DefaultSaltLength)
+	if parsed.NeedsRehash() {
+		t.Error("NeedsRehash() = true at the defaults")
+	}
+
+	if info := GetAlgorithmInfo(); info["wrapped_algorithm"] != WrappedAlgorithmIdentifier {
+		t.Errorf("GetAlgorithmInfo()[wrapped_algorithm] = %v", info["wrapped_algorithm"])
+	}
+}
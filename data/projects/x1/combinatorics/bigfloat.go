@@ -0,0 +1,138 @@
+package combinatorics
+
+import "math/big"
+
+// The big.Float helpers below work at a caller-chosen working precision and
+// leave rounding to the requested precision to the caller, who adds guard
+// bits for the error of each step.
+
+// bigExp returns e^x. The argument is halved until it is tiny, the Taylor
+// series summed, and the result squared back up.
+func bigExp(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return new(big.Float).SetPrec(prec).SetInt64(1)
+	}
+	halvings := 0
+	if e := x.MantExp(nil); e > -8 {
+		halvings = e + 8
+	}
+	wp := prec + uint(halvings) + 16
+
+	z := new(big.Float).SetPrec(wp).SetMantExp(x, -halvings)
+	sum := new(big.Float).SetPrec(wp).SetInt64(1)
+	term := new(big.Float).SetPrec(wp).SetInt64(1)
+	for k := int64(1); ; k++ {
+		term.Mul(term, z)
+		term.Quo(term, new(big.Float).SetInt64(k))
+		sum.Add(sum, term)
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(wp) {
+			break
+		}
+	}
+	for i := 0; i < halvings; i++ {
+		sum.Mul(sum, sum)
+	}
+	return sum.SetPrec(prec)
+}
+
+// bigLog returns ln x for x > 0 as ln m + e ln 2 with x = m 2^e and
+// m in [0.5, 1)
+func bigLog(x *big.Float, prec uint) *big.Float {
+	mant := new(big.Float)
+	e := x.MantExp(mant)
+	wp := prec + 64
+
+	// ln m = 2 atanh((m-1)/(m+1)), with |(m-1)/(m+1)| <= 1/3
+	m := new(big.Float).SetPrec(wp).Set(mant)
+	num := new(big.Float).SetPrec(wp).Sub(m, big.NewFloat(1))
+	den := new(big.Float).SetPrec(wp).Add(m, big.NewFloat(1))
+	result := bigAtanh(num.Quo(num, den), wp)
+	result.Mul(result, big.NewFloat(2))
+
+	if e != 0 {
+		ln2 := bigLn2(wp)
+		result.Add(result, ln2.Mul(ln2, new(big.Float).SetInt64(int64(e))))
+	}
+	return result.SetPrec(prec)
+}
+
+// bigLn2 returns ln 2 = 2 atanh(1/3)
+func bigLn2(prec uint) *big.Float {
+	third := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), big.NewFloat(3))
+	ln2 := bigAtanh(third, prec)
+	return ln2.Mul(ln2, big.NewFloat(2))
+}
+
+// bigAtanh sums z + z^3/3 + z^5/5 + ... for |z| well below 1
+func bigAtanh(z *big.Float, prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	if z.Sign() == 0 {
+		return sum
+	}
+	z2 := new(big.Float).SetPrec(prec).Mul(z, z)
+	power := new(big.Float).SetPrec(prec).Set(z)
+	term := new(big.Float).SetPrec(prec)
+	for k := int64(3); ; k += 2 {
+		power.Mul(power, z2)
+		term.Quo(power, new(big.Float).SetInt64(k))
+		sum.Add(sum, term)
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			return sum
+		}
+	}
+}
+
+// bigPi returns pi from Machin's formula 16 atan(1/5) - 4 atan(1/239)
+func bigPi(prec uint) *big.Float {
+	wp := prec + 16
+	pi := bigAtanInv(5, wp)
+	pi.Mul(pi, big.NewFloat(16))
+	pi.Sub(pi, new(big.Float).Mul(bigAtanInv(239, wp), big.NewFloat(4)))
+	return pi.SetPrec(prec)
+}
+
+// bigAtanInv returns atan(1/q) = 1/q - 1/(3q^3) + 1/(5q^5) - ...
+func bigAtanInv(q int64, prec uint) *big.Float {
+	power := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), new(big.Float).SetInt64(q))
+	sum := new(big.Float).SetPrec(prec).Set(power)
+	q2 := new(big.Float).SetInt64(q * q)
+	term := new(big.Float).SetPrec(prec)
+	for k := int64(3); ; k += 2 {
+		power.Quo(power, q2)
+		term.Quo(power, new(big.Float).SetInt64(k))
+		if k%4 == 3 {
+			sum.Sub(sum, term)
+		} else {
+			sum.Add(sum, term)
+		}
+		if term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			return sum
+		}
+	}
+}
+
+// bernoulli yields the Bernoulli numbers B0, B1, B2, ... one at a time
+// using the Akiyama-Tanigawa algorithm (with B1 = +1/2)
+type bernoulli struct {
+	row []*big.Rat
+}
+
+func (b *bernoulli) next() *big.Rat {
+	m := len(b.row)
+	b.row = append(b.row, big.NewRat(1, int64(m)+1))
+	for j := m; j >= 1; j-- {
+		d := new(big.Rat).Sub(b.row[j-1], b.row[j])
+		b.row[j-1] = d.Mul(d, big.NewRat(int64(j), 1))
+	}
+	return new(big.Rat).Set(b.row[0])
+}
+
+// nextEven skips to the next Bernoulli number with an even index >= 2
+func (b *bernoulli) nextEven() *big.Rat {
+	for {
+		v := b.next()
+		if m := len(b.row) - 1; m >= 2 && m%2 == 0 {
+			return v
+		}
+	}
+}
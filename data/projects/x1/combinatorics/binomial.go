@@ -0,0 +1,221 @@
+package combinatorics
+
+import (
+	"math"
+	"math/big"
+)
+
+// Permutations returns nPr = n!/(n-k)!, the number of ordered selections of
+// k out of n items. It is 0 when k > n.
+func Permutations(n, k int) (int64, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return 0, err
+	}
+	if k > n {
+		return 0, nil
+	}
+
+	result := int64(1)
+	for i := n - k + 1; i <= n; i++ {
+		var ok bool
+		if result, ok = mulInt64(result, int64(i)); !ok {
+			return 0, overflow("Permutations", n, k)
+		}
+	}
+	return result, nil
+}
+
+// BigPermutations returns nPr exactly
+func BigPermutations(n, k int) (*big.Int, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return nil, err
+	}
+	if k > n {
+		return new(big.Int), nil
+	}
+	return new(big.Int).MulRange(int64(n-k+1), int64(n)), nil
+}
+
+// Binomial returns nCr = n!/(k!(n-k)!), the number of k-element subsets of
+// n items. It is 0 when k > n. Every intermediate value is itself a binomial
+// coefficient no larger than the result, so it only fails when the result
+// does not fit in an int64.
+func Binomial(n, k int) (int64, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return 0, err
+	}
+	if k > n {
+		return 0, nil
+	}
+	if k > n-k {
+		k = n - k
+	}
+
+	// C(n-k+i, i) = C(n-k+i-1, i-1) * (n-k+i) / i
+	result := int64(1)
+	for i := 1; i <= k; i++ {
+		var ok bool
+		if result, ok = mulDivInt64(result, int64(n-k+i), int64(i)); !ok {
+			return 0, overflow("Binomial", n, k)
+		}
+	}
+	return result, nil
+}
+
+// binomialProductRatio is how many times smaller than n the smaller of k
+// and n-k must be for BigBinomial to divide products instead of sieving
+// primes up to n. Around it both take about as long.
+const binomialProductRatio = 128
+
+// BigBinomial returns nCr exactly. When min(k, n-k) is small next to n it is
+// n(n-1)...(n-k+1) / k!; otherwise it is assembled from its prime
+// factorization (Legendre's formula), so no factorial is ever formed.
+func BigBinomial(n, k int) (*big.Int, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return nil, err
+	}
+	if k > n {
+		return new(big.Int), nil
+	}
+	if k > n-k {
+		k = n - k
+	}
+	if k <= n/binomialProductRatio {
+		c := new(big.Int).MulRange(int64(n-k+1), int64(n))
+		return c.Quo(c, new(big.Int).MulRange(1, int64(k))), nil
+	}
+	return bigMultinomial(n, []int{k, n - k}), nil
+}
+
+// maxBinomialRow is the last row of Pascal's triangle whose middle entry,
+// C(66, 33), fits in an int64
+const maxBinomialRow = 66
+
+// BinomialRow returns row n of Pascal's triangle, C(n, 0) through C(n, n)
+func BinomialRow(n int) ([]int64, error) {
+	if err := checkNonNegative(n); err != nil {
+		return nil, err
+	}
+	// Refuse before allocating a row that could never be filled
+	if n > maxBinomialRow {
+		return nil, overflow("BinomialRow", n)
+	}
+
+	row := make([]int64, n+1)
+	row[0] = 1
+	for i := 0; i < n/2; i++ {
+		var ok bool
+		if row[i+1], ok = mulDivInt64(row[i], int64(n-i), int64(i+1)); !ok {
+			return nil, overflow("BinomialRow", n)
+		}
+	}
+	// The row is symmetric
+	for i := n/2 + 1; i <= n; i++ {
+		row[i] = row[n-i]
+	}
+	return row, nil
+}
+
+// BigBinomialRow returns row n of Pascal's triangle exactly
+func BigBinomialRow(n int) ([]*big.Int, error) {
+	if err := checkNonNegative(n); err != nil {
+		return nil, err
+	}
+
+	row := make([]*big.Int, n+1)
+	row[0] = big.NewInt(1)
+	for i := 0; i < n/2; i++ {
+		next := new(big.Int).Mul(row[i], big.NewInt(int64(n-i)))
+		row[i+1] = next.Quo(next, big.NewInt(int64(i+1)))
+	}
+	for i := n/2 + 1; i <= n; i++ {
+		row[i] = new(big.Int).Set(row[n-i])
+	}
+	return row, nil
+}
+
+// Multinomial returns (k1+k2+...)!/(k1!k2!...), the number of ways to split
+// k1+k2+... items into groups of the given sizes. It is computed as a product
+// of binomial coefficients.
+func Multinomial(ks ...int) (int64, error) {
+	if err := checkNonNegative(ks...); err != nil {
+		return 0, err
+	}
+
+	result := int64(1)
+	n := 0
+	for _, k := range ks {
+		if n > math.MaxInt-k {
+			return 0, overflow("Multinomial", ks...)
+		}
+		n += k
+		c, err := Binomial(n, k)
+		if err != nil {
+			return 0, overflow("Multinomial", ks...)
+		}
+		var ok bool
+		if result, ok = mulInt64(result, c); !ok {
+			return 0, overflow("Multinomial", ks...)
+		}
+	}
+	return result, nil
+}
+
+// BigMultinomial returns the multinomial coefficient exactly
+func BigMultinomial(ks ...int) (*big.Int, error) {
+	if err := checkNonNegative(ks...); err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, k := range ks {
+		if n > math.MaxInt-k {
+			return nil, overflow("BigMultinomial", ks...)
+		}
+		n += k
+	}
+	return bigMultinomial(n, ks), nil
+}
+
+// bigMultinomial returns n!/(k1!k2!...) for ks summing to n from the
+// exponent of every prime p <= n, legendre(n, p) - sum legendre(ki, p)
+func bigMultinomial(n int, ks []int) *big.Int {
+	primes := primesUpTo(n)
+	exps := make([]int, len(primes))
+	for i, p := range primes {
+		e := legendre(n, p)
+		for _, k := range ks {
+			e -= legendre(k, p)
+		}
+		exps[i] = e
+	}
+	return productOfPowers(primes, exps)
+}
+
+// Catalan returns the nth Catalan number C(2n, n)/(n+1), computed with the
+// recurrence C(i+1) = C(i) * 2(2i+1)/(i+2) so C(2n, n) itself may overflow
+func Catalan(n int) (int64, error) {
+	if err := checkNonNegative(n); err != nil {
+		return 0, err
+	}
+
+	result := int64(1)
+	for i := 0; i < n; i++ {
+		var ok bool
+		if result, ok = mulDivInt64(result, int64(2*(2*i+1)), int64(i+2)); !ok {
+			return 0, overflow("Catalan", n)
+		}
+	}
+	return result, nil
+}
+
+// BigCatalan returns the nth Catalan number exactly
+func BigCatalan(n int) (*big.Int, error) {
+	if err := checkNonNegative(n); err != nil {
+		return nil, err
+	}
+	if n > (math.MaxInt-1)/2 {
+		return nil, overflow("BigCatalan", n)
+	}
+	c := bigMultinomial(2*n, []int{n, n})
+	return c.Quo(c, big.NewInt(int64(n)+1)), nil
+}
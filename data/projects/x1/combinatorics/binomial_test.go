@@ -0,0 +1,202 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPermutations(t *testing.T) {
+	tests := []struct {
+		n, k int
+		want int64
+	}{
+		{5, 0, 1},
+		{5, 2, 20},
+		{10, 10, 3628800},
+		{3, 5, 0},
+		{20, 20, 2432902008176640000},
+	}
+
+	for _, tt := range tests {
+		got, err := Permutations(tt.n, tt.k)
+		if err != nil {
+			t.Errorf("Permutations(%d, %d) returned error: %v", tt.n, tt.k, err)
+		}
+		if got != tt.want {
+			t.Errorf("Permutations(%d, %d) = %d, want %d", tt.n, tt.k, got, tt.want)
+		}
+		b, err := BigPermutations(tt.n, tt.k)
+		if err != nil || b.Int64() != tt.want {
+			t.Errorf("BigPermutations(%d, %d) = %v, %v, want %d", tt.n, tt.k, b, err, tt.want)
+		}
+	}
+
+	var overflow *OverflowError
+	if _, err := Permutations(21, 21); !errors.As(err, &overflow) {
+		t.Errorf("Permutations(21, 21) error = %v, want *OverflowError", err)
+	}
+	if _, err := Permutations(-1, 0); !errors.Is(err, ErrNegative) {
+		t.Errorf("Permutations(-1, 0) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestBinomial(t *testing.T) {
+	tests := []struct {
+		n, k int
+		want int64
+	}{
+		{0, 0, 1},
+		{5, 2, 10},
+		{52, 5, 2598960},
+		{10, 11, 0},
+		{66, 33, 7219428434016265740},
+		{1000, 2, 499500},
+	}
+
+	for _, tt := range tests {
+		got, err := Binomial(tt.n, tt.k)
+		if err != nil {
+			t.Errorf("Binomial(%d, %d) returned error: %v", tt.n, tt.k, err)
+		}
+		if got != tt.want {
+			t.Errorf("Binomial(%d, %d) = %d, want %d", tt.n, tt.k, got, tt.want)
+		}
+	}
+
+	// C(67, 33) = 14226520737620288370 is the first coefficient past int64
+	var overflow *OverflowError
+	if _, err := Binomial(67, 33); !errors.As(err, &overflow) {
+		t.Errorf("Binomial(67, 33) error = %v, want *OverflowError", err)
+	}
+	if _, err := Binomial(5, -1); !errors.Is(err, ErrNegative) {
+		t.Errorf("Binomial(5, -1) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestBigBinomial(t *testing.T) {
+	// Both sides of binomialProductRatio, from either end of the row
+	for _, n := range []int{0, 1, 7, 68, 500, 1001, 5000} {
+		r := n / binomialProductRatio
+		for _, k := range []int{0, 1, r, r + 1, n / 3, n / 2, max(n-r-1, 0), n - r, n} {
+			got, err := BigBinomial(n, k)
+			if err != nil {
+				t.Fatalf("BigBinomial(%d, %d) returned error: %v", n, k, err)
+			}
+			want := new(big.Int).Binomial(int64(n), int64(k))
+			if got.Cmp(want) != 0 {
+				t.Errorf("BigBinomial(%d, %d) = %s, want %s", n, k, got, want)
+			}
+		}
+	}
+
+	if got, _ := BigBinomial(68, 34); got.String() != "28453041475240576740" {
+		t.Errorf("BigBinomial(68, 34) = %s", got)
+	}
+	// Small k must not sieve the primes up to n
+	if got, _ := BigBinomial(200_000_000, 2); got.Int64() != 200_000_000*199_999_999/2 {
+		t.Errorf("BigBinomial(2e8, 2) = %s, want %d", got, 200_000_000*199_999_999/2)
+	}
+	if got, _ := BigBinomial(3, 4); got.Sign() != 0 {
+		t.Errorf("BigBinomial(3, 4) = %s, want 0", got)
+	}
+}
+
+func TestBinomialRow(t *testing.T) {
+	row, err := BinomialRow(6)
+	if err != nil {
+		t.Fatalf("BinomialRow(6) returned error: %v", err)
+	}
+	want := []int64{1, 6, 15, 20, 15, 6, 1}
+	if len(row) != len(want) {
+		t.Fatalf("BinomialRow(6) = %v, want %v", row, want)
+	}
+	for i := range want {
+		if row[i] != want[i] {
+			t.Fatalf("BinomialRow(6) = %v, want %v", row, want)
+		}
+	}
+
+	if _, err := BinomialRow(66); err != nil {
+		t.Errorf("BinomialRow(66) returned error: %v", err)
+	}
+	var overflow *OverflowError
+	if _, err := BinomialRow(67); !errors.As(err, &overflow) {
+		t.Errorf("BinomialRow(67) error = %v, want *OverflowError", err)
+	}
+	// Too large to even allocate
+	if _, err := BinomialRow(1 << 40); !errors.As(err, &overflow) {
+		t.Errorf("BinomialRow(1<<40) error = %v, want *OverflowError", err)
+	}
+
+	bigRow, err := BigBinomialRow(100)
+	if err != nil {
+		t.Fatalf("BigBinomialRow(100) returned error: %v", err)
+	}
+	for k, got := range bigRow {
+		if want := new(big.Int).Binomial(100, int64(k)); got.Cmp(want) != 0 {
+			t.Errorf("BigBinomialRow(100)[%d] = %s, want %s", k, got, want)
+		}
+	}
+}
+
+func TestMultinomial(t *testing.T) {
+	tests := []struct {
+		ks   []int
+		want int64
+	}{
+		{nil, 1},
+		{[]int{3}, 1},
+		{[]int{2, 3}, 10},
+		{[]int{1, 4, 4, 2}, 34650}, // MISSISSIPPI
+		{[]int{0, 5, 0}, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := Multinomial(tt.ks...)
+		if err != nil {
+			t.Errorf("Multinomial(%v) returned error: %v", tt.ks, err)
+		}
+		if got != tt.want {
+			t.Errorf("Multinomial(%v) = %d, want %d", tt.ks, got, tt.want)
+		}
+		b, err := BigMultinomial(tt.ks...)
+		if err != nil || b.Int64() != tt.want {
+			t.Errorf("BigMultinomial(%v) = %v, %v, want %d", tt.ks, b, err, tt.want)
+		}
+	}
+
+	// 60!/(20!20!20!) is about 5.8e26
+	var overflow *OverflowError
+	if _, err := Multinomial(20, 20, 20); !errors.As(err, &overflow) {
+		t.Errorf("Multinomial(20, 20, 20) error = %v, want *OverflowError", err)
+	}
+	got, _ := BigMultinomial(20, 20, 20)
+	if got.String() != "577831214478475823831865900" {
+		t.Errorf("BigMultinomial(20, 20, 20) = %s", got)
+	}
+}
+
+func TestCatalan(t *testing.T) {
+	want := []int64{1, 1, 2, 5, 14, 42, 132, 429, 1430, 4862, 16796}
+	for n, w := range want {
+		got, err := Catalan(n)
+		if err != nil || got != w {
+			t.Errorf("Catalan(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+
+	// C(70, 35) overflows but the 35th Catalan number does not
+	if got, err := Catalan(35); err != nil || got != 3116285494907301262 {
+		t.Errorf("Catalan(35) = %d, %v, want 3116285494907301262", got, err)
+	}
+	var overflow *OverflowError
+	if _, err := Catalan(36); !errors.As(err, &overflow) {
+		t.Errorf("Catalan(36) error = %v, want *OverflowError", err)
+	}
+
+	got, err := BigCatalan(36)
+	if err != nil || got.String() != "11959798385860453492" {
+		t.Errorf("BigCatalan(36) = %v, %v, want 11959798385860453492", got, err)
+	}
+}
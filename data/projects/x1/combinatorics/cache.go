@@ -0,0 +1,130 @@
+package combinatorics
+
+import (
+	"math/big"
+	"sync"
+)
+
+// DefaultCacheInterval is the spacing of checkpoints in a Cache created
+// with NewCache(0)
+const DefaultCacheInterval = 4096
+
+// maxCacheCheckpoints bounds the checkpoints a Cache keeps; the least
+// recently used one is dropped first
+const maxCacheCheckpoints = 16
+
+// Cache memoizes factorials for repeated nearby queries. n <= 20 is answered
+// from the precomputed table; larger n start from the checkpoint
+// (k*interval)! just below n, so a repeat query costs at most one product
+// over interval numbers plus one large multiplication.
+//
+// A missing checkpoint is extended from the one before it when that is
+// cached, and computed with ParallelFactorial otherwise, so a cold query
+// costs about as much as ParallelFactorial itself. At most 16 checkpoints
+// are kept, the least recently used being dropped, so memory stays within
+// 16 times the size of the largest n! queried. A Cache is safe for
+// concurrent use.
+type Cache struct {
+	interval int
+
+	mu sync.Mutex
+	// checkpoints maps k to (k*interval)!; entries are never modified
+	checkpoints map[int]*big.Int
+	// used lists the keys of checkpoints from least to most recently used
+	used []int
+
+	// grow serializes computing checkpoints, so concurrent queries for the
+	// same range compute it once while cached ranges are still answered
+	grow sync.Mutex
+}
+
+// NewCache returns an empty Cache with checkpoints every interval numbers;
+// interval <= 0 means DefaultCacheInterval
+func NewCache(interval int) *Cache {
+	if interval <= 0 {
+		interval = DefaultCacheInterval
+	}
+	return &Cache{interval: interval, checkpoints: make(map[int]*big.Int)}
+}
+
+// Factorial returns n! as a new big.Int the caller may modify
+func (c *Cache) Factorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n < len(smallFactorials) {
+		return big.NewInt(smallFactorials[n]), nil
+	}
+
+	i := n / c.interval
+	base := c.checkpoint(i)
+	start := i * c.interval
+	if start == n {
+		return new(big.Int).Set(base), nil
+	}
+	rest := parallelRangeProduct(uint64(start+1), uint64(n), 0)
+	return rest.Mul(rest, base), nil
+}
+
+// Checkpoints returns the number of checkpoints currently kept
+func (c *Cache) Checkpoints() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.checkpoints)
+}
+
+// checkpoint returns (i*interval)!, computing and keeping it if missing
+func (c *Cache) checkpoint(i int) *big.Int {
+	if v := c.lookup(i); v != nil {
+		return v
+	}
+
+	c.grow.Lock()
+	defer c.grow.Unlock()
+
+	// Another goroutine may have computed it meanwhile
+	if v := c.lookup(i); v != nil {
+		return v
+	}
+	var v *big.Int
+	if prev := c.lookup(i - 1); prev != nil {
+		v = parallelRangeProduct(uint64((i-1)*c.interval+1), uint64(i*c.interval), 0)
+		v.Mul(v, prev)
+	} else {
+		v, _ = ParallelFactorial(i*c.interval, 0)
+	}
+	c.store(i, v)
+	return v
+}
+
+// lookup returns checkpoint i, or nil if it is not kept, and marks it as
+// the most recently used
+func (c *Cache) lookup(i int) *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.checkpoints[i]
+	if !ok {
+		return nil
+	}
+	for j, k := range c.used {
+		if k == i {
+			copy(c.used[j:], c.used[j+1:])
+			c.used[len(c.used)-1] = i
+			break
+		}
+	}
+	return v
+}
+
+// store keeps checkpoint i, dropping the least recently used one if the
+// Cache is full
+func (c *Cache) store(i int, v *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.used) == maxCacheCheckpoints {
+		delete(c.checkpoints, c.used[0])
+		c.used = c.used[1:]
+	}
+	c.checkpoints[i] = v
+	c.used = append(c.used, i)
+}
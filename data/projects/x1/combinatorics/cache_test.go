@@ -0,0 +1,107 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestCacheFactorial(t *testing.T) {
+	c := NewCache(100)
+	for _, n := range []int{0, 5, 20, 21, 99, 100, 101, 250, 1000, 999, 3, 1234} {
+		want, _ := BigFactorial(n)
+		got, err := c.Factorial(n)
+		if err != nil || got.Cmp(want) != 0 {
+			t.Fatalf("Cache.Factorial(%d) = %v, %v, want %s", n, got, err, want)
+		}
+	}
+	// Only the checkpoints below the queries are kept: 0, 1, 2, 10, 9 and 12
+	if got := c.Checkpoints(); got != 6 {
+		t.Errorf("Checkpoints() = %d after n = 1234, want 6", got)
+	}
+
+	if _, err := c.Factorial(-1); !errors.Is(err, ErrNegative) {
+		t.Errorf("Cache.Factorial(-1) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestCacheBounded(t *testing.T) {
+	c := NewCache(10)
+	for n := 0; n <= 1000; n += 25 {
+		want, _ := BigFactorial(n)
+		if got, _ := c.Factorial(n); got.Cmp(want) != 0 {
+			t.Fatalf("Cache.Factorial(%d) returned a wrong value", n)
+		}
+	}
+	if got := c.Checkpoints(); got != maxCacheCheckpoints {
+		t.Errorf("Checkpoints() = %d, want the limit of %d", got, maxCacheCheckpoints)
+	}
+
+	// The dropped checkpoints are recomputed when asked for again
+	want, _ := BigFactorial(55)
+	if got, _ := c.Factorial(55); got.Cmp(want) != 0 {
+		t.Error("Cache.Factorial(55) returned a wrong value after its checkpoint was dropped")
+	}
+}
+
+func TestCacheResultIsCopy(t *testing.T) {
+	c := NewCache(10)
+	f, _ := c.Factorial(30)
+	f.SetInt64(0)
+	want, _ := BigFactorial(30)
+	if got, _ := c.Factorial(30); got.Cmp(want) != 0 {
+		t.Error("modifying a returned value changed the cached checkpoint")
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	const n = 3000
+	want := make([]*big.Int, n+1)
+	f := big.NewInt(1)
+	for i := 0; i <= n; i++ {
+		if i > 0 {
+			f = new(big.Int).Mul(f, big.NewInt(int64(i)))
+		}
+		want[i] = f
+	}
+
+	c := NewCache(64)
+	var wg sync.WaitGroup
+	errs := make(chan int, 16)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// Each goroutine walks the range in a different order, so
+			// growing and reading the checkpoints interleave
+			for i := 0; i <= n; i += 37 {
+				m := (i*(g+1) + g*101) % (n + 1)
+				if got, err := c.Factorial(m); err != nil || got.Cmp(want[m]) != 0 {
+					errs <- m
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for m := range errs {
+		t.Errorf("concurrent Cache.Factorial(%d) returned a wrong value", m)
+	}
+}
+
+func BenchmarkCacheFactorialCold200000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewCache(0).Factorial(200_000)
+	}
+}
+
+func BenchmarkCacheFactorial200000(b *testing.B) {
+	c := NewCache(0)
+	c.Factorial(200_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Factorial(200_000 - i%DefaultCacheInterval)
+	}
+}
@@ -0,0 +1,108 @@
+package combinatorics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// ErrNegative is returned for arguments where a function is not defined
+// because they are negative
+var ErrNegative = errors.New("not defined for negative numbers")
+
+// ErrDomain is returned for other arguments outside the domain of a
+// function, such as the poles of the gamma function
+var ErrDomain = errors.New("argument outside the domain of the function")
+
+// OverflowError is returned when a result does not fit in the fixed-size
+// integer type of the function. The Big variants return exact results of any
+// size.
+type OverflowError struct {
+	Func string
+	Args []int
+}
+
+func (e *OverflowError) Error() string {
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = fmt.Sprint(a)
+	}
+	return fmt.Sprintf("%s(%s) overflows", e.Func, strings.Join(args, ", "))
+}
+
+func overflow(fn string, args ...int) error {
+	return &OverflowError{Func: fn, Args: args}
+}
+
+// checkNonNegative returns ErrNegative if any argument is negative
+func checkNonNegative(args ...int) error {
+	for _, a := range args {
+		if a < 0 {
+			return ErrNegative
+		}
+	}
+	return nil
+}
+
+// mulInt64 returns a*b for non-negative a and b, and false if the product
+// does not fit in an int64
+func mulInt64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(lo), true
+}
+
+// addInt64 returns a+b for non-negative a and b, and false if the sum does
+// not fit in an int64
+func addInt64(a, b int64) (int64, bool) {
+	if a > math.MaxInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// mulDivInt64 returns a*b/d for non-negative a and b and positive d, using a
+// 128-bit intermediate so the product may exceed int64 as long as the
+// quotient does not. The division must be exact.
+func mulDivInt64(a, b, d int64) (int64, bool) {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi >= uint64(d) {
+		return 0, false
+	}
+	q, _ := bits.Div64(hi, lo, uint64(d))
+	if q > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(q), true
+}
+
+// mulSignedInt64 returns a*b for any signs, and false if the product does
+// not fit in an int64
+func mulSignedInt64(a, b int64) (int64, bool) {
+	hi, lo := bits.Mul64(absInt64(a), absInt64(b))
+	if hi != 0 {
+		return 0, false
+	}
+	if (a < 0) != (b < 0) {
+		if lo > 1<<63 {
+			return 0, false
+		}
+		return int64(-lo), true
+	}
+	if lo > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(lo), true
+}
+
+// absInt64 returns |a| as a uint64, which also holds |math.MinInt64|
+func absInt64(a int64) uint64 {
+	if a < 0 {
+		return -uint64(a)
+	}
+	return uint64(a)
+}
@@ -0,0 +1,101 @@
+// Package combinatorics computes factorials and the counting functions built
+// on them: permutations, binomial and multinomial coefficients, Catalan and
+// Stirling numbers, and variants such as multifactorials, derangements and
+// primorials. Every function has an overflow-checked fixed-size form
+// and an exact big.Int form, and none of the exact forms divides huge
+// factorials to get its result. For real arguments it provides the gamma
+// function and log-factorials in float64 and at arbitrary big.Float
+// precision.
+package combinatorics
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// smallFactorials holds 0! through 20!, every factorial that fits in an
+// int64
+var smallFactorials = func() (table [21]int64) {
+	table[0] = 1
+	for i := 1; i < len(table); i++ {
+		table[i] = table[i-1] * int64(i)
+	}
+	return table
+}()
+
+// Factorial calculates the factorial of a non-negative integer n.
+// Returns ErrNegative if n is negative and an *OverflowError if n! does not
+// fit in an int (n > 20 on 64-bit platforms). Results come from a
+// precomputed table.
+func Factorial(n int) (int, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+	if n >= len(smallFactorials) || smallFactorials[n] > math.MaxInt {
+		return 0, overflow("Factorial", n)
+	}
+	return int(smallFactorials[n]), nil
+}
+
+// BigFactorial calculates n! exactly. It splits n! into its power of two and
+// its odd part and builds the odd part from balanced products of odd numbers
+// (Luschny's split-recursive algorithm), so the expensive multiplications are
+// between numbers of similar size. It is practical for n in the millions.
+func BigFactorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n < 2 {
+		return big.NewInt(1), nil
+	}
+
+	// The odd part of n! is the product over k of the odd numbers in
+	// (n>>(k+1), n>>k], each taken k+1 times. Walking k downwards, p holds
+	// the odd numbers up to n>>k and r accumulates the product of every p.
+	p, r := big.NewInt(1), big.NewInt(1)
+	next := uint64(1)
+	shift, h, high := uint(0), 0, 1
+	for log2n := bits.Len(uint(n)) - 1; h != n; log2n-- {
+		shift += uint(h)
+		h = n >> log2n
+		low := high
+		high = (h - 1) | 1
+		if count := (high - low) / 2; count > 0 {
+			p.Mul(p, progressionProduct(next+2, 2, count))
+			next += 2 * uint64(count)
+			r.Mul(r, p)
+		}
+	}
+
+	// The powers of two: n! has n - popcount(n) factors of 2, and the odd
+	// part already excludes them
+	return r.Lsh(r, shift), nil
+}
+
+// progressionLeaf is the number of factors multiplied as machine words
+// before falling back to big.Int
+const progressionLeaf = 16
+
+// progressionProduct returns first * (first+step) * ... for count factors,
+// splitting the range in halves so both operands stay balanced
+func progressionProduct(first, step uint64, count int) *big.Int {
+	if count <= progressionLeaf {
+		result := big.NewInt(1)
+		acc := uint64(1)
+		for i := 0; i < count; i++ {
+			f := first + step*uint64(i)
+			if hi, lo := bits.Mul64(acc, f); hi == 0 {
+				acc = lo
+				continue
+			}
+			result.Mul(result, new(big.Int).SetUint64(acc))
+			acc = f
+		}
+		return result.Mul(result, new(big.Int).SetUint64(acc))
+	}
+
+	half := count / 2
+	left := progressionProduct(first, step, half)
+	return left.Mul(left, progressionProduct(first+step*uint64(half), step, count-half))
+}
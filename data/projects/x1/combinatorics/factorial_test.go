@@ -0,0 +1,83 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFactorial(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{0, 1},
+		{1, 1},
+		{5, 120},
+		{10, 3628800},
+		{20, 2432902008176640000},
+	}
+
+	for _, tt := range tests {
+		got, err := Factorial(tt.n)
+		if err != nil {
+			t.Errorf("Factorial(%d) returned error: %v", tt.n, err)
+		}
+		if got != tt.want {
+			t.Errorf("Factorial(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFactorialErrors(t *testing.T) {
+	if _, err := Factorial(-1); !errors.Is(err, ErrNegative) {
+		t.Errorf("Factorial(-1) error = %v, want ErrNegative", err)
+	}
+
+	_, err := Factorial(21)
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) {
+		t.Fatalf("Factorial(21) error = %v, want *OverflowError", err)
+	}
+	if overflow.Func != "Factorial" || len(overflow.Args) != 1 || overflow.Args[0] != 21 {
+		t.Errorf("Factorial(21) error = %+v", overflow)
+	}
+}
+
+func TestBigFactorial(t *testing.T) {
+	// Compare against the straightforward product for every small n, which
+	// exercises each shape of the split-recursive loop
+	want := big.NewInt(1)
+	for n := 0; n <= 300; n++ {
+		if n > 0 {
+			want.Mul(want, big.NewInt(int64(n)))
+		}
+		got, err := BigFactorial(n)
+		if err != nil {
+			t.Fatalf("BigFactorial(%d) returned error: %v", n, err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("BigFactorial(%d) = %s, want %s", n, got, want)
+		}
+	}
+
+	if _, err := BigFactorial(-1); !errors.Is(err, ErrNegative) {
+		t.Errorf("BigFactorial(-1) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestBigFactorialLarge(t *testing.T) {
+	n := 20000
+	got, err := BigFactorial(n)
+	if err != nil {
+		t.Fatalf("BigFactorial(%d) returned error: %v", n, err)
+	}
+	want := new(big.Int).MulRange(1, int64(n))
+	if got.Cmp(want) != 0 {
+		t.Errorf("BigFactorial(%d) differs from MulRange", n)
+	}
+	// 20000! has 77338 decimal digits
+	if digits := len(got.String()); digits != 77338 {
+		t.Errorf("BigFactorial(%d) has %d digits, want 77338", n, digits)
+	}
+}
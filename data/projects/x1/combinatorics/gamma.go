@@ -0,0 +1,162 @@
+package combinatorics
+
+import (
+	"math"
+	"math/big"
+)
+
+// maxExactGamma is the largest integer argument for which BigGamma and
+// BigLogFactorial use the exact factorial instead of Stirling's series
+const maxExactGamma = 1 << 12
+
+// Gamma returns the gamma function, which extends the factorial to real
+// arguments: Gamma(n+1) = n!. It follows math.Gamma for special cases, e.g.
+// it is NaN at negative integers.
+func Gamma(x float64) float64 {
+	return math.Gamma(x)
+}
+
+// LogGamma returns ln|Gamma(x)| and the sign of Gamma(x), as math.Lgamma
+func LogGamma(x float64) (float64, int) {
+	return math.Lgamma(x)
+}
+
+// LogFactorial returns ln n!. It does not overflow for any n, and is
+// accurate to a few ulps.
+func LogFactorial(n int) (float64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+	lg, _ := math.Lgamma(float64(n) + 1)
+	return lg, nil
+}
+
+// StirlingLogFactorial approximates ln x! for real x > 0 with the first
+// terms of Stirling's series,
+//
+//	ln x! ~ x ln x - x + ln(2 pi x)/2 + sum B(2k) / (2k(2k-1) x^(2k-1))
+//
+// and returns the approximation with a bound on its truncation error. The
+// series is asymptotic: the error after k terms is smaller in magnitude than
+// the first omitted term, which shrinks until k is about pi x and then
+// grows, so more terms do not always help for small x. The bound does not
+// include float64 rounding.
+func StirlingLogFactorial(x float64, terms int) (approx, bound float64, err error) {
+	if x <= 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, 0, ErrDomain
+	}
+	if terms < 0 {
+		return 0, 0, ErrNegative
+	}
+
+	approx = x*math.Log(x) - x + math.Log(2*math.Pi*x)/2
+	var b bernoulli
+	for k := 1; ; k++ {
+		b2k, _ := b.nextEven().Float64()
+		term := b2k / (float64(2*k*(2*k-1)) * math.Pow(x, float64(2*k-1)))
+		if k > terms {
+			return approx, math.Abs(term), nil
+		}
+		approx += term
+	}
+}
+
+// BigGamma returns Gamma(x) for x > 0 at prec bits of precision. Integer
+// arguments up to 4096 are exact factorials rounded to prec; others use
+// Stirling's series at a shifted argument.
+func BigGamma(x *big.Float, prec uint) (*big.Float, error) {
+	if x.Sign() <= 0 || x.IsInf() {
+		return nil, ErrDomain
+	}
+	if n, acc := x.Int64(); acc == big.Exact && n <= maxExactGamma {
+		f, _ := BigFactorial(int(n - 1))
+		return new(big.Float).SetPrec(prec).SetInt(f), nil
+	}
+
+	wp := gammaWorkingPrec(x, prec)
+	lg, shift := bigLogGammaShifted(x, wp)
+	g := bigExp(lg, wp)
+	return g.Quo(g, shift).SetPrec(prec), nil
+}
+
+// BigLogGamma returns ln Gamma(x) for x > 0 at prec bits of precision. The
+// error is relative to the result except close to the zeros at 1 and 2,
+// where it is absolute.
+func BigLogGamma(x *big.Float, prec uint) (*big.Float, error) {
+	if x.Sign() <= 0 || x.IsInf() {
+		return nil, ErrDomain
+	}
+	if n, acc := x.Int64(); acc == big.Exact && (n == 1 || n == 2) {
+		return new(big.Float).SetPrec(prec), nil
+	}
+
+	wp := gammaWorkingPrec(x, prec)
+	lg, shift := bigLogGammaShifted(x, wp)
+	return lg.Sub(lg, bigLog(shift, wp)).SetPrec(prec), nil
+}
+
+// BigLogFactorial returns ln n! at prec bits of precision
+func BigLogFactorial(n int, prec uint) (*big.Float, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n < 2 {
+		return new(big.Float).SetPrec(prec), nil
+	}
+	if n <= maxExactGamma {
+		f, _ := BigFactorial(n)
+		return bigLog(new(big.Float).SetPrec(prec+64).SetInt(f), prec), nil
+	}
+	x := new(big.Float).SetInt64(int64(n))
+	return BigLogGamma(x.Add(x, big.NewFloat(1)), prec)
+}
+
+// gammaWorkingPrec adds guard bits for the magnitude of ln Gamma at the
+// shifted argument, whose absolute error becomes the relative error of
+// Gamma
+func gammaWorkingPrec(x *big.Float, prec uint) uint {
+	wp := prec + 64
+	if e := x.MantExp(nil); e > 0 {
+		wp += uint(e)
+	}
+	return wp
+}
+
+// bigLogGammaShifted returns ln Gamma(x+n) and x(x+1)...(x+n-1) for a shift
+// n that makes x+n at least wp, which lets Stirling's series reach an error
+// below 2^-wp
+func bigLogGammaShifted(x *big.Float, wp uint) (lg, shift *big.Float) {
+	y := new(big.Float).SetPrec(wp).Set(x)
+	shift = new(big.Float).SetPrec(wp).SetInt64(1)
+	threshold := new(big.Float).SetInt64(int64(wp))
+	for y.Cmp(threshold) < 0 {
+		shift.Mul(shift, y)
+		y.Add(y, big.NewFloat(1))
+	}
+
+	// (y - 1/2) ln y - y + ln(2 pi)/2
+	half := big.NewFloat(0.5)
+	lg = new(big.Float).SetPrec(wp).Sub(y, half)
+	lg.Mul(lg, bigLog(y, wp))
+	lg.Sub(lg, y)
+	twoPi := bigPi(wp)
+	twoPi.Mul(twoPi, big.NewFloat(2))
+	lnTwoPi := bigLog(twoPi, wp)
+	lg.Add(lg, lnTwoPi.Mul(lnTwoPi, half))
+
+	// + sum B(2k) / (2k(2k-1) y^(2k-1))
+	var b bernoulli
+	y2 := new(big.Float).SetPrec(wp).Mul(y, y)
+	power := new(big.Float).SetPrec(wp).Set(y)
+	term := new(big.Float).SetPrec(wp)
+	for k := int64(1); ; k++ {
+		term.SetRat(b.nextEven())
+		term.Quo(term, new(big.Float).SetInt64(2*k*(2*k-1)))
+		term.Quo(term, power)
+		lg.Add(lg, term)
+		if term.Sign() == 0 || term.MantExp(nil) < -int(wp) {
+			return lg, shift
+		}
+		power.Mul(power, y2)
+	}
+}
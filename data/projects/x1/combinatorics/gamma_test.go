@@ -0,0 +1,206 @@
+package combinatorics
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// Reference values to 120 significant digits
+const (
+	refSqrtPi        = "1.77245385090551602729816748334114518279754945612238712821380778985291128459103218137495065673854466541622682362428257066623615"
+	refGammaThird    = "2.67893853470774763365569294097467764412868937795730110095042832759041761016774381954098288904118878941915904920007226333571909"
+	refGammaFive2    = "1.32934038817913702047362561250585888709816209209179034616035584238968346344327413603121299255390849906217011771821192799967711"
+	refLogGamma10_5  = "13.9406252194037636331612378879718494797994528048474955812462859023236712453900620104764502624990842056752273256705937807175199"
+	refLogGammaMilli = "6.90717888538385368251234466807698250215996161744610915294577108000583211008551361799743634092082942960678253663376551628922814"
+	refLogFact100    = "363.739375555563490144079993369655638027823921062887274727679448876775944447979019914101000241972549319615773559722930531198015"
+	refLogFact10000  = "82108.9278368143534553850300635124060826388065270351766288108989839149095453489526553122249248752709825463362866875369270272458"
+	refLogFact1e5    = "1051299.22189912186512927810820611085524934452314812138111395023219670794822874055357423907789714789735801358882280756637011887"
+	refPi            = "3.14159265358979323846264338327950288419716939937510582097494459230781640628620899862803482534211706798214808651328230664709384"
+	refLn2           = "0.693147180559945309417232121458176568075500134360255254120680009493393621969694715605863326996418687542001481020570685733685520"
+)
+
+// checkBigFloat fails unless got agrees with the decimal reference to within
+// a few units in the last of prec bits
+func checkBigFloat(t *testing.T, name string, got *big.Float, ref string, prec uint) {
+	t.Helper()
+	want, _, err := big.ParseFloat(ref, 10, 512, big.ToNearestEven)
+	if err != nil {
+		t.Fatalf("bad reference %q: %v", ref, err)
+	}
+	diff := new(big.Float).SetPrec(512).Sub(got, want)
+	tolerance := new(big.Float).SetMantExp(want, -int(prec)+3)
+	if diff.Abs(diff).Cmp(tolerance.Abs(tolerance)) > 0 {
+		t.Errorf("%s at %d bits = %s, want %s", name, prec, got.Text('g', 40), want.Text('g', 40))
+	}
+}
+
+func TestGamma(t *testing.T) {
+	for n := 0; n <= 20; n++ {
+		f, _ := Factorial(n)
+		if got := Gamma(float64(n + 1)); math.Abs(got-float64(f)) > 1e-14*float64(f) {
+			t.Errorf("Gamma(%d) = %g, want %d", n+1, got, f)
+		}
+	}
+	if got := Gamma(0.5); math.Abs(got-math.Sqrt(math.Pi)) > 1e-15 {
+		t.Errorf("Gamma(0.5) = %v, want sqrt(pi)", got)
+	}
+	if got := Gamma(-2); !math.IsNaN(got) {
+		t.Errorf("Gamma(-2) = %v, want NaN", got)
+	}
+	if lg, sign := LogGamma(-0.5); sign != -1 || math.Abs(lg-math.Log(2*math.Sqrt(math.Pi))) > 1e-15 {
+		t.Errorf("LogGamma(-0.5) = %v, %d", lg, sign)
+	}
+}
+
+func TestLogFactorial(t *testing.T) {
+	tests := []struct {
+		n    int
+		want float64
+	}{
+		{0, 0},
+		{1, 0},
+		{100, 363.73937555556349},
+		{100000, 1051299.2218991219},
+		{1 << 40, 2.93854237636712e13},
+	}
+
+	for _, tt := range tests {
+		got, err := LogFactorial(tt.n)
+		if err != nil {
+			t.Errorf("LogFactorial(%d) returned error: %v", tt.n, err)
+		}
+		if math.Abs(got-tt.want) > 1e-14*math.Max(1, tt.want) {
+			t.Errorf("LogFactorial(%d) = %.17g, want %.17g", tt.n, got, tt.want)
+		}
+	}
+
+	if _, err := LogFactorial(-1); !errors.Is(err, ErrNegative) {
+		t.Errorf("LogFactorial(-1) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestStirlingLogFactorial(t *testing.T) {
+	// The true error must lie within the reported bound, and the bound must
+	// shrink with more terms while x is large compared to the term count
+	exact := 363.73937555556349014 // ln 100!
+	prevBound := math.Inf(1)
+	for terms := 0; terms <= 4; terms++ {
+		approx, bound, err := StirlingLogFactorial(100, terms)
+		if err != nil {
+			t.Fatalf("StirlingLogFactorial(100, %d) returned error: %v", terms, err)
+		}
+		if actual := math.Abs(approx - exact); actual > bound+1e-12 {
+			t.Errorf("StirlingLogFactorial(100, %d) error %g exceeds bound %g", terms, actual, bound)
+		}
+		if bound >= prevBound {
+			t.Errorf("StirlingLogFactorial(100, %d) bound %g did not shrink from %g", terms, bound, prevBound)
+		}
+		prevBound = bound
+	}
+
+	// With no correction terms the bound is the first term, 1/(12x)
+	_, bound, _ := StirlingLogFactorial(10, 0)
+	if math.Abs(bound-1.0/120) > 1e-15 {
+		t.Errorf("StirlingLogFactorial(10, 0) bound = %v, want 1/120", bound)
+	}
+
+	// Works for real arguments too: ln(0.5!) = ln(sqrt(pi)/2)
+	approx, bound, _ := StirlingLogFactorial(0.5, 1)
+	if actual := math.Abs(approx - math.Log(math.Sqrt(math.Pi)/2)); actual > bound {
+		t.Errorf("StirlingLogFactorial(0.5, 1) error %g exceeds bound %g", actual, bound)
+	}
+
+	if _, _, err := StirlingLogFactorial(0, 1); !errors.Is(err, ErrDomain) {
+		t.Errorf("StirlingLogFactorial(0, 1) error = %v, want ErrDomain", err)
+	}
+}
+
+func TestBigHelpers(t *testing.T) {
+	for _, prec := range []uint{53, 200, 400} {
+		checkBigFloat(t, "bigPi", bigPi(prec), refPi, prec)
+		checkBigFloat(t, "bigLn2", bigLn2(prec), refLn2, prec)
+		e := bigExp(big.NewFloat(1), prec)
+		checkBigFloat(t, "bigLog(bigExp(1))", bigLog(e, prec), "1", prec)
+	}
+}
+
+func TestBigGamma(t *testing.T) {
+	for _, prec := range []uint{53, 128, 400} {
+		half := new(big.Float).SetPrec(prec).SetFloat64(0.5)
+		g, err := BigGamma(half, prec)
+		if err != nil {
+			t.Fatalf("BigGamma(0.5) returned error: %v", err)
+		}
+		checkBigFloat(t, "BigGamma(1/2)", g, refSqrtPi, prec)
+
+		third := new(big.Float).SetPrec(prec+64).Quo(big.NewFloat(1), big.NewFloat(3))
+		g, _ = BigGamma(third, prec)
+		checkBigFloat(t, "BigGamma(1/3)", g, refGammaThird, prec)
+
+		g, _ = BigGamma(big.NewFloat(2.5), prec)
+		checkBigFloat(t, "BigGamma(5/2)", g, refGammaFive2, prec)
+	}
+
+	g, err := BigGamma(big.NewFloat(31), 200)
+	if err != nil {
+		t.Fatalf("BigGamma(31) returned error: %v", err)
+	}
+	f, _ := BigFactorial(30)
+	if want := new(big.Float).SetPrec(200).SetInt(f); g.Cmp(want) != 0 {
+		t.Errorf("BigGamma(31) = %s, want 30!", g.Text('g', 40))
+	}
+
+	for _, x := range []float64{0, -1.5} {
+		if _, err := BigGamma(big.NewFloat(x), 64); !errors.Is(err, ErrDomain) {
+			t.Errorf("BigGamma(%v) error = %v, want ErrDomain", x, err)
+		}
+	}
+}
+
+func TestBigLogGamma(t *testing.T) {
+	for _, prec := range []uint{53, 256, 400} {
+		lg, err := BigLogGamma(big.NewFloat(10.5), prec)
+		if err != nil {
+			t.Fatalf("BigLogGamma(10.5) returned error: %v", err)
+		}
+		checkBigFloat(t, "BigLogGamma(10.5)", lg, refLogGamma10_5, prec)
+
+		milli := new(big.Float).SetPrec(prec+64).Quo(big.NewFloat(1), big.NewFloat(1000))
+		lg, _ = BigLogGamma(milli, prec)
+		checkBigFloat(t, "BigLogGamma(0.001)", lg, refLogGammaMilli, prec)
+	}
+
+	if lg, _ := BigLogGamma(big.NewFloat(2), 64); lg.Sign() != 0 {
+		t.Errorf("BigLogGamma(2) = %s, want 0", lg)
+	}
+}
+
+func TestBigLogFactorial(t *testing.T) {
+	tests := []struct {
+		n   int
+		ref string
+	}{
+		{100, refLogFact100},
+		{10000, refLogFact10000},
+		{100000, refLogFact1e5},
+	}
+
+	for _, prec := range []uint{53, 300} {
+		for _, tt := range tests {
+			got, err := BigLogFactorial(tt.n, prec)
+			if err != nil {
+				t.Fatalf("BigLogFactorial(%d) returned error: %v", tt.n, err)
+			}
+			checkBigFloat(t, "BigLogFactorial", got, tt.ref, prec)
+		}
+	}
+
+	if got, _ := BigLogFactorial(1, 64); got.Sign() != 0 {
+		t.Errorf("BigLogFactorial(1) = %s, want 0", got)
+	}
+	if _, err := BigLogFactorial(-3, 64); !errors.Is(err, ErrNegative) {
+		t.Errorf("BigLogFactorial(-3) error = %v, want ErrNegative", err)
+	}
+}
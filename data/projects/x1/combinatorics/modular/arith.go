@@ -0,0 +1,187 @@
+package modular
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// MulMod returns a*b mod m without overflow, using a 128-bit product
+func MulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a%m, b%m)
+	_, r := bits.Div64(hi, lo, m)
+	return r
+}
+
+// PowMod returns a^e mod m by square-and-multiply
+func PowMod(a, e, m uint64) uint64 {
+	result := 1 % m
+	a %= m
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = MulMod(result, a, m)
+		}
+		a = MulMod(a, a, m)
+	}
+	return result
+}
+
+// InverseMod returns the x in [0, m) with a*x = 1 mod m, or
+// ErrNotInvertible if a and m share a factor
+func InverseMod(a, m uint64) (uint64, error) {
+	if m == 0 {
+		return 0, ErrModulus
+	}
+	// Extended Euclid, tracking the coefficient of a modulo m so it stays
+	// unsigned
+	r0, r1 := m, a%m
+	t0, t1 := uint64(0), uint64(1)
+	for r1 != 0 {
+		q := r0 / r1
+		r0, r1 = r1, r0-q*r1
+		t0, t1 = t1, subMod(t0, MulMod(q, t1, m), m)
+	}
+	if r0 != 1 {
+		return 0, ErrNotInvertible
+	}
+	return t0 % m, nil
+}
+
+// subMod returns a-b mod m for a, b < m
+func subMod(a, b, m uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return m - (b - a)
+}
+
+// IsPrime reports whether n is prime. It runs Miller-Rabin with the first
+// twelve primes as bases, which is deterministic for every uint64.
+func IsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	bases := []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+	for _, p := range bases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	d, s := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		s++
+	}
+	for _, a := range bases {
+		x := PowMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < s; i++ {
+			x = MulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// primePower is one factor p^e of a modulus
+type primePower struct {
+	p  uint64
+	e  int
+	pe uint64
+}
+
+// factorize returns the prime power factors of m in increasing order of p,
+// by trial division for small factors and Pollard's rho for the rest
+func factorize(m uint64) []primePower {
+	counts := make(map[uint64]int)
+	for _, p := range []uint64{2, 3, 5, 7, 11, 13} {
+		for m%p == 0 {
+			counts[p]++
+			m /= p
+		}
+	}
+	var split func(n uint64)
+	split = func(n uint64) {
+		if n == 1 {
+			return
+		}
+		if IsPrime(n) {
+			counts[n]++
+			return
+		}
+		d := pollardRho(n)
+		split(d)
+		split(n / d)
+	}
+	split(m)
+
+	factors := make([]primePower, 0, len(counts))
+	for p, e := range counts {
+		pe := uint64(1)
+		for i := 0; i < e; i++ {
+			pe *= p
+		}
+		factors = append(factors, primePower{p: p, e: e, pe: pe})
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].p < factors[j].p })
+	return factors
+}
+
+// pollardRho returns a non-trivial factor of the composite n, which has no
+// factor below 17, using Brent's variant: gcds are taken over batches of
+// rhoBatch accumulated differences, backtracking if a batch overshoots
+func pollardRho(n uint64) uint64 {
+	const rhoBatch = 128
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return addMod(MulMod(x, x, n), c, n) }
+		y, q, g := uint64(2), uint64(1), uint64(1)
+		var x, ys uint64
+		for r := 1; g == 1; r *= 2 {
+			x = y
+			for i := 0; i < r; i++ {
+				y = f(y)
+			}
+			for k := 0; k < r && g == 1; k += rhoBatch {
+				ys = y
+				for i := 0; i < rhoBatch && i < r-k; i++ {
+					y = f(y)
+					q = MulMod(q, absDiff(x, y), n)
+				}
+				g = gcd(q, n)
+			}
+		}
+		if g == n {
+			// The batch hit every factor at once; redo it one step at a time
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd(absDiff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g
+		}
+	}
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,83 @@
+package modular
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestMulModPowMod(t *testing.T) {
+	const m = 1<<63 + 29 // large enough that naive products overflow
+	a, b := uint64(1<<62+12345), uint64(1<<61+999)
+	want := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	want.Mod(want, new(big.Int).SetUint64(m))
+	if got := MulMod(a, b, m); got != want.Uint64() {
+		t.Errorf("MulMod() = %d, want %s", got, want)
+	}
+
+	wantPow := new(big.Int).Exp(big.NewInt(3), big.NewInt(1_000_003), new(big.Int).SetUint64(m))
+	if got := PowMod(3, 1_000_003, m); got != wantPow.Uint64() {
+		t.Errorf("PowMod() = %d, want %s", got, wantPow)
+	}
+	if got := PowMod(5, 0, 1); got != 0 {
+		t.Errorf("PowMod(5, 0, 1) = %d, want 0", got)
+	}
+}
+
+func TestInverseMod(t *testing.T) {
+	for _, m := range []uint64{2, 7, 12, 1_000_000_007, 1 << 40} {
+		for a := uint64(1); a < 50; a++ {
+			inv, err := InverseMod(a, m)
+			if gcd(a, m) != 1 {
+				if !errors.Is(err, ErrNotInvertible) {
+					t.Errorf("InverseMod(%d, %d) error = %v, want ErrNotInvertible", a, m, err)
+				}
+				continue
+			}
+			if err != nil || MulMod(a, inv, m) != 1%m {
+				t.Errorf("InverseMod(%d, %d) = %d, %v", a, m, inv, err)
+			}
+		}
+	}
+}
+
+func TestIsPrime(t *testing.T) {
+	primes := []uint64{2, 3, 37, 41, 1_000_003, 1_000_000_007, 998_244_353, 18446744073709551557}
+	composites := []uint64{0, 1, 4, 561, 1_000_001, 3215031751, 3825123056546413051, 18446744073709551615}
+	for _, p := range primes {
+		if !IsPrime(p) {
+			t.Errorf("IsPrime(%d) = false", p)
+		}
+	}
+	for _, c := range composites {
+		if IsPrime(c) {
+			t.Errorf("IsPrime(%d) = true", c)
+		}
+	}
+}
+
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		m    uint64
+		want []primePower
+	}{
+		{1, nil},
+		{360, []primePower{{2, 3, 8}, {3, 2, 9}, {5, 1, 5}}},
+		{1_000_000_007 * 998_244_353, []primePower{{998_244_353, 1, 998_244_353}, {1_000_000_007, 1, 1_000_000_007}}},
+		{4294967291 * 4294967291, []primePower{{4294967291, 2, 4294967291 * 4294967291}}},
+	}
+
+	for _, tt := range tests {
+		got := factorize(tt.m)
+		if len(got) != len(tt.want) {
+			t.Errorf("factorize(%d) = %v, want %v", tt.m, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("factorize(%d) = %v, want %v", tt.m, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package modular computes factorials and binomial coefficients modulo m for
+// n far too large to materialize n!. The products are linear in the distance
+// from n to the nearer end of [1, p), so n! mod a prime p needs n or p-1-n to
+// be at most MaxProductTerms (it is simply 0 for n >= p): 1e12! mod (1e12+39)
+// is quick, while 1e11! mod (1e12+39) returns ErrTooLarge. A Table
+// precomputes products and inverses modulo one prime power for repeated
+// queries; FactorialMod and BinomialMod accept any modulus by splitting it
+// into prime powers and recombining with the Chinese remainder theorem.
+package modular
+
+import (
+	"errors"
+	"sort"
+)
+
+// MaxProductTerms bounds the multiplications FactorialMod and BinomialMod
+// spend on one prime power; inputs that need more return ErrTooLarge
+const MaxProductTerms = 1 << 24
+
+var (
+	// ErrModulus is returned for a zero modulus or exponent
+	ErrModulus = errors.New("modulus must be positive")
+	// ErrNotPrime is returned when a Table is requested for a non-prime base
+	ErrNotPrime = errors.New("table base must be prime")
+	// ErrTableTooLarge is returned when a prime power exceeds MaxTableModulus
+	ErrTableTooLarge = errors.New("prime power too large for a table")
+	// ErrNotInvertible is returned when an inverse does not exist
+	ErrNotInvertible = errors.New("value is not invertible modulo m")
+	// ErrTooLarge is returned when a result needs more than MaxProductTerms
+	// multiplications modulo one prime power
+	ErrTooLarge = errors.New("too many terms to multiply")
+)
+
+// FactorialMod returns n! mod m. The result is 0 whenever n >= m, and for
+// each prime power p^e of m whenever n! has e factors of p. A prime modulus
+// uses Wilson's theorem, (p-1)! = -1 mod p, to multiply from whichever end
+// of [1, p) is closer, so the cost is O(min(n, p-n)). Other moduli are
+// reduced per prime power and recombined; a prime power p^e with fewer than
+// e factors of p in n! needs n < e*p and costs O(n).
+//
+// Those products are linear, so they are limited to MaxProductTerms: n!
+// mod p is supported for n <= MaxProductTerms or p-1-n <= MaxProductTerms,
+// and other inputs that are not simply 0 return ErrTooLarge.
+func FactorialMod(n, m uint64) (uint64, error) {
+	if m == 0 {
+		return 0, ErrModulus
+	}
+	if m == 1 || n >= m {
+		return 0, nil
+	}
+	if IsPrime(m) {
+		return factorialModPrime(n, m)
+	}
+
+	factors := factorize(m)
+	residues := make([]uint64, len(factors))
+	for i, f := range factors {
+		var err error
+		switch {
+		case legendre(n, f.p) >= uint64(f.e):
+			residues[i] = 0
+		case f.e == 1:
+			residues[i], err = factorialModPrime(n, f.p)
+		case n > MaxProductTerms:
+			err = ErrTooLarge
+		default:
+			residues[i] = productMod(1, n, f.pe)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return crt(residues, factors), nil
+}
+
+// BinomialMod returns C(n, k) mod m, which is 0 when k > n. It builds no
+// tables; use a Table to answer repeated queries for one prime power. Each
+// prime p of m uses Lucas' theorem with digit binomials computed directly.
+// A prime power p^e multiplies the r = min(k, n-k) terms of C(n, r) if r is
+// below p^e, and otherwise applies Granville's theorem with the unit
+// products it needs computed in one pass over [1, p^e). Either way a prime
+// power that would take more than MaxProductTerms steps returns ErrTooLarge.
+func BinomialMod(n, k, m uint64) (uint64, error) {
+	if m == 0 {
+		return 0, ErrModulus
+	}
+	if m == 1 || k > n {
+		return 0, nil
+	}
+	r := min(k, n-k)
+
+	factors := factorize(m)
+	residues := make([]uint64, len(factors))
+	for i, f := range factors {
+		var err error
+		switch {
+		case f.e == 1:
+			residues[i], err = lucasPrime(n, r, f.p)
+		case r < f.pe && r <= MaxProductTerms:
+			residues[i] = binomialProduct(n, r, f)
+		case f.pe <= MaxProductTerms:
+			residues[i] = binomialGranville(n, r, f)
+		default:
+			err = ErrTooLarge
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return crt(residues, factors), nil
+}
+
+// factorialModPrime returns n! mod p for n < p, or ErrTooLarge if both ends
+// of [1, p) are more than MaxProductTerms away
+func factorialModPrime(n, p uint64) (uint64, error) {
+	rest := p - 1 - n
+	if min(n, rest) > MaxProductTerms {
+		return 0, ErrTooLarge
+	}
+	if rest < n {
+		// n! * (n+1)...(p-1) = -1, so n! = -1 / ((n+1)...(p-1))
+		inv, _ := InverseMod(productMod(n+1, p-1, p), p)
+		return (p - inv) % p, nil
+	}
+	return productMod(1, n, p), nil
+}
+
+// productMod returns lo * (lo+1) * ... * hi mod m
+func productMod(lo, hi, m uint64) uint64 {
+	result := 1 % m
+	for i := lo; i <= hi && i >= lo; i++ {
+		result = MulMod(result, i, m)
+	}
+	return result
+}
+
+// lucasPrime is Lucas' theorem without a Table, computing each digit
+// binomial C(ni, ki) as a product of min(ki, ni-ki) terms
+func lucasPrime(n, k, p uint64) (uint64, error) {
+	result := 1 % p
+	for k > 0 {
+		ni, ki := n%p, k%p
+		if ki > ni {
+			return 0, nil
+		}
+		ki = min(ki, ni-ki)
+		if ki > MaxProductTerms {
+			return 0, ErrTooLarge
+		}
+		// C(ni, ki) = ni...(ni-ki+1) / ki!, with ki! invertible since ki < p
+		inv, _ := InverseMod(productMod(1, ki, p), p)
+		c := MulMod(productMod(ni-ki+1, ni, p), inv, p)
+		result = MulMod(result, c, p)
+		n /= p
+		k /= p
+	}
+	return result, nil
+}
+
+// binomialProduct returns C(n, k) mod p^e as the product of (n-i)/(i+1)
+// for i < k. The factors of p are counted apart so the remaining units can
+// be inverted, at O(k) cost for any n.
+func binomialProduct(n, k uint64, f primePower) uint64 {
+	num, den := 1%f.pe, 1%f.pe
+	var exp uint64
+	for i := uint64(0); i < k; i++ {
+		a, b := n-i, i+1
+		for a%f.p == 0 {
+			a /= f.p
+			exp++
+		}
+		// C(n, i+1) is an integer, so exp cannot drop below 0
+		for b%f.p == 0 {
+			b /= f.p
+			exp--
+		}
+		num = MulMod(num, a, f.pe)
+		den = MulMod(den, b, f.pe)
+	}
+	if exp >= uint64(f.e) {
+		return 0
+	}
+	inv, _ := InverseMod(den, f.pe)
+	return MulMod(MulMod(num, inv, f.pe), PowMod(f.p, exp, f.pe), f.pe)
+}
+
+// binomialGranville returns C(n, k) mod p^e like Table.Binomial, but
+// computes only the unit products it needs, in one pass over [1, p^e)
+func binomialGranville(n, k uint64, f primePower) uint64 {
+	args := []uint64{n, k, n - k}
+	need := []uint64{f.pe - 1}
+	for _, x := range args {
+		for ; x > 0; x /= f.p {
+			need = append(need, x%f.pe)
+		}
+	}
+	units := unitProducts(need, f.p, f.pe)
+
+	// primeFree mirrors Table.FactorialPrimeFree
+	primeFree := func(x uint64) (uint64, uint64) {
+		result := 1 % f.pe
+		var exp uint64
+		for x > 0 {
+			if blocks := x / f.pe; blocks > 0 {
+				result = MulMod(result, PowMod(units[f.pe-1], blocks, f.pe), f.pe)
+			}
+			result = MulMod(result, units[x%f.pe], f.pe)
+			x /= f.p
+			exp += x
+		}
+		return result, exp
+	}
+
+	num, c := primeFree(n)
+	a, ca := primeFree(k)
+	b, cb := primeFree(n - k)
+	c -= ca + cb
+	if c >= uint64(f.e) {
+		return 0
+	}
+	den, _ := InverseMod(MulMod(a, b, f.pe), f.pe)
+	return MulMod(MulMod(num, den, f.pe), PowMod(f.p, c, f.pe), f.pe)
+}
+
+// unitProducts maps each x < pe in xs to the product of the j <= x coprime
+// to p, mod pe
+func unitProducts(xs []uint64, p, pe uint64) map[uint64]uint64 {
+	sorted := append([]uint64(nil), xs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	units := make(map[uint64]uint64, len(sorted))
+	product, j := 1%pe, uint64(1)
+	for _, x := range sorted {
+		for ; j <= x; j++ {
+			if j%p != 0 {
+				product = MulMod(product, j, pe)
+			}
+		}
+		units[x] = product
+	}
+	return units
+}
+
+// legendre returns the exponent of p in n!
+func legendre(n, p uint64) uint64 {
+	var e uint64
+	for n >= p {
+		n /= p
+		e += n
+	}
+	return e
+}
+
+// crt combines residues modulo pairwise coprime prime powers into the
+// residue modulo their product
+func crt(residues []uint64, factors []primePower) uint64 {
+	m := uint64(1)
+	for _, f := range factors {
+		m *= f.pe
+	}
+
+	var x uint64
+	for i, f := range factors {
+		rest := m / f.pe
+		inv, _ := InverseMod(rest%f.pe, f.pe)
+		x = addMod(x, MulMod(residues[i], MulMod(rest, inv, m), m), m)
+	}
+	return x
+}
+
+// addMod returns a+b mod m for a, b < m without overflowing
+func addMod(a, b, m uint64) uint64 {
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
@@ -0,0 +1,122 @@
+package modular
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFactorialMod(t *testing.T) {
+	moduli := []uint64{1, 2, 97, 360, 1 << 20, 999_983 * 2, 1_000_000_007, 4294967291 * 4294967291}
+	fact := big.NewInt(1)
+	for n := uint64(0); n <= 300; n++ {
+		if n > 0 {
+			fact.Mul(fact, new(big.Int).SetUint64(n))
+		}
+		for _, m := range moduli {
+			want := new(big.Int).Mod(fact, new(big.Int).SetUint64(m)).Uint64()
+			got, err := FactorialMod(n, m)
+			if err != nil || got != want {
+				t.Fatalf("FactorialMod(%d, %d) = %d, %v, want %d", n, m, got, err, want)
+			}
+		}
+	}
+
+	if _, err := FactorialMod(5, 0); !errors.Is(err, ErrModulus) {
+		t.Errorf("FactorialMod(5, 0) error = %v, want ErrModulus", err)
+	}
+}
+
+func TestFactorialModWilson(t *testing.T) {
+	// Near p the Wilson path only multiplies the few numbers above n:
+	// (p-2)! = 1 and (p-3)! = (p-1)/2 mod p
+	const p = 1_000_000_000_039
+	if got, _ := FactorialMod(p-2, p); got != 1 {
+		t.Errorf("FactorialMod(p-2, p) = %d, want 1", got)
+	}
+	if got, _ := FactorialMod(p-3, p); got != (p-1)/2 {
+		t.Errorf("FactorialMod(p-3, p) = %d, want %d", got, uint64((p-1)/2))
+	}
+	if got, _ := FactorialMod(p, p); got != 0 {
+		t.Errorf("FactorialMod(p, p) = %d, want 0", got)
+	}
+}
+
+func TestFactorialModTooLarge(t *testing.T) {
+	// Both ends of [1, p) are far from n, so the product would be linear in n
+	if _, err := FactorialMod(400_000_000, 1_000_000_007); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("FactorialMod(4e8, 1e9+7) error = %v, want ErrTooLarge", err)
+	}
+	// A prime power needs the long product unless n! has e factors of p
+	const q = 4294967291
+	if _, err := FactorialMod(q+1, q*q); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("FactorialMod(q+1, q^2) error = %v, want ErrTooLarge", err)
+	}
+	if got, err := FactorialMod(2*q, q*q); err != nil || got != 0 {
+		t.Errorf("FactorialMod(2q, q^2) = %d, %v, want 0", got, err)
+	}
+}
+
+func TestBinomialMod(t *testing.T) {
+	moduli := []uint64{1, 2, 10, 97, 1 << 10, 1_000_000_007, 1_000_000_007 * 998_244_353}
+	for n := int64(0); n <= 80; n++ {
+		for k := int64(0); k <= n+1; k++ {
+			c := new(big.Int).Binomial(n, k)
+			if k > n {
+				c.SetInt64(0)
+			}
+			for _, m := range moduli {
+				want := new(big.Int).Mod(c, new(big.Int).SetUint64(m)).Uint64()
+				got, err := BinomialMod(uint64(n), uint64(k), m)
+				if err != nil || got != want {
+					t.Fatalf("BinomialMod(%d, %d, %d) = %d, %v, want %d", n, k, m, got, err, want)
+				}
+			}
+		}
+	}
+
+	// Lucas over two base-p digits of a large prime
+	const p = 1_000_000_007
+	n, k := uint64(3*p+10), uint64(p+4)
+	c10_4 := uint64(210)
+	c3_1 := uint64(3)
+	if got, _ := BinomialMod(n, k, p); got != c10_4*c3_1 {
+		t.Errorf("BinomialMod(%d, %d, p) = %d, want %d", n, k, got, c10_4*c3_1)
+	}
+
+	// Small k needs no table, even for prime powers a Table cannot hold
+	if got, err := BinomialMod(10, 3, 16_777_213); err != nil || got != 120 {
+		t.Errorf("BinomialMod(10, 3, 16777213) = %d, %v, want 120", got, err)
+	}
+	if got, err := BinomialMod(10, 3, 4294967291*4294967291); err != nil || got != 120 {
+		t.Errorf("BinomialMod with a large prime square = %d, %v, want 120", got, err)
+	}
+	if _, err := BinomialMod(1<<40, 1<<30, 4294967291*4294967291); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("BinomialMod with a large prime square and large k error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestBinomialModPrimePowers(t *testing.T) {
+	// Both the direct product and the Granville pass must agree with a Table
+	cases := []struct {
+		p uint64
+		e int
+	}{{2, 10}, {3, 7}, {5, 3}, {997, 2}}
+	args := [][2]uint64{
+		{1_000_000, 3}, {1_000_000, 999_990}, {1_000_000, 500_000},
+		{1 << 40, 12_345}, {1<<40 + 17, 1 << 39}, {123_456_789_012, 98_765_432_109},
+	}
+	for _, c := range cases {
+		table, err := NewTable(c.p, c.e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range args {
+			want := table.Binomial(a[0], a[1])
+			got, err := BinomialMod(a[0], a[1], table.Modulus())
+			if err != nil || got != want {
+				t.Errorf("BinomialMod(%d, %d, %d) = %d, %v, want %d", a[0], a[1], table.Modulus(), got, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package modular
+
+// MaxTableModulus bounds the prime power a Table precomputes, which costs
+// 8 bytes per residue (24 for primes, which also get inverse tables)
+const MaxTableModulus = 1 << 24
+
+// Table holds precomputed products modulo a prime power p^e so factorials
+// and binomial coefficients of any n can be reduced in O(log_p n) steps.
+// A Table is read-only after construction and safe for concurrent use.
+type Table struct {
+	p, pe uint64
+	e     int
+	// units[i] is the product of the j <= i coprime to p, mod p^e
+	units []uint64
+	// fact and invFact are i! and its inverse mod p, for e == 1 only
+	fact, invFact []uint64
+}
+
+// NewTable precomputes the tables for the modulus p^e. It returns
+// ErrNotPrime if p is not prime and ErrTableTooLarge if p^e exceeds
+// MaxTableModulus.
+func NewTable(p uint64, e int) (*Table, error) {
+	if !IsPrime(p) {
+		return nil, ErrNotPrime
+	}
+	if e < 1 {
+		return nil, ErrModulus
+	}
+	pe := uint64(1)
+	for i := 0; i < e; i++ {
+		if pe > MaxTableModulus/p {
+			return nil, ErrTableTooLarge
+		}
+		pe *= p
+	}
+
+	t := &Table{p: p, pe: pe, e: e, units: make([]uint64, pe)}
+	t.units[0] = 1
+	for i := uint64(1); i < pe; i++ {
+		if i%p == 0 {
+			t.units[i] = t.units[i-1]
+		} else {
+			t.units[i] = MulMod(t.units[i-1], i, pe)
+		}
+	}
+
+	if e == 1 {
+		// For a prime the unit products are the factorials, and the
+		// inverses follow from inv(i) = -(p/i) inv(p mod i)
+		t.fact = t.units
+		inv := make([]uint64, p)
+		inv[1] = 1 % p
+		for i := uint64(2); i < p; i++ {
+			inv[i] = p - MulMod(p/i, inv[p%i], p)
+		}
+		t.invFact = make([]uint64, p)
+		t.invFact[0] = 1 % p
+		for i := uint64(1); i < p; i++ {
+			t.invFact[i] = MulMod(t.invFact[i-1], inv[i], p)
+		}
+	}
+	return t, nil
+}
+
+// Modulus returns p^e
+func (t *Table) Modulus() uint64 {
+	return t.pe
+}
+
+// Factorial returns n! mod p^e, which is 0 once n! has e factors of p
+func (t *Table) Factorial(n uint64) uint64 {
+	free, exp := t.FactorialPrimeFree(n)
+	if exp >= uint64(t.e) {
+		return 0
+	}
+	return MulMod(free, PowMod(t.p, exp, t.pe), t.pe)
+}
+
+// FactorialPrimeFree returns n! with every factor of p removed, mod p^e,
+// and the number of factors removed. It uses the generalized Wilson
+// theorem: the numbers coprime to p in each full block of p^e multiply to
+// the same unit, so
+//
+//	(n!)_p = units(p^e-1)^(n / p^e) * units(n mod p^e) * ((n/p)!)_p
+func (t *Table) FactorialPrimeFree(n uint64) (uint64, uint64) {
+	result := 1 % t.pe
+	var exp uint64
+	for n > 0 {
+		if blocks := n / t.pe; blocks > 0 {
+			result = MulMod(result, PowMod(t.units[t.pe-1], blocks, t.pe), t.pe)
+		}
+		result = MulMod(result, t.units[n%t.pe], t.pe)
+		n /= t.p
+		exp += n
+	}
+	return result, exp
+}
+
+// Binomial returns C(n, k) mod p^e, which is 0 when k > n. Prime moduli use
+// Lucas' theorem over the base-p digits of n and k; prime powers use
+// Granville's generalization, dividing prime-free factorials and restoring
+// the p^c where c is the number of carries when adding k and n-k in base p.
+func (t *Table) Binomial(n, k uint64) uint64 {
+	if k > n {
+		return 0
+	}
+	if t.e == 1 {
+		return t.lucas(n, k)
+	}
+
+	num, c := t.FactorialPrimeFree(n)
+	a, ca := t.FactorialPrimeFree(k)
+	b, cb := t.FactorialPrimeFree(n - k)
+	c -= ca + cb
+	if c >= uint64(t.e) {
+		return 0
+	}
+	// Prime-free factorials are units, so the inverse always exists
+	den, _ := InverseMod(MulMod(a, b, t.pe), t.pe)
+	result := MulMod(num, den, t.pe)
+	return MulMod(result, PowMod(t.p, c, t.pe), t.pe)
+}
+
+// lucas returns C(n, k) mod p as the product of C(ni, ki) over the base-p
+// digits of n and k
+func (t *Table) lucas(n, k uint64) uint64 {
+	result := 1 % t.p
+	for k > 0 {
+		ni, ki := n%t.p, k%t.p
+		if ki > ni {
+			return 0
+		}
+		c := MulMod(t.fact[ni], MulMod(t.invFact[ki], t.invFact[ni-ki], t.p), t.p)
+		result = MulMod(result, c, t.p)
+		n /= t.p
+		k /= t.p
+	}
+	return result
+}
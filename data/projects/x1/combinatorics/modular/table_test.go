@@ -0,0 +1,107 @@
+package modular
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNewTable(t *testing.T) {
+	if _, err := NewTable(15, 1); !errors.Is(err, ErrNotPrime) {
+		t.Errorf("NewTable(15, 1) error = %v, want ErrNotPrime", err)
+	}
+	if _, err := NewTable(2, 30); !errors.Is(err, ErrTableTooLarge) {
+		t.Errorf("NewTable(2, 30) error = %v, want ErrTableTooLarge", err)
+	}
+	if _, err := NewTable(7, 0); !errors.Is(err, ErrModulus) {
+		t.Errorf("NewTable(7, 0) error = %v, want ErrModulus", err)
+	}
+	tb, err := NewTable(3, 4)
+	if err != nil || tb.Modulus() != 81 {
+		t.Errorf("NewTable(3, 4) = %v, %v, want modulus 81", tb, err)
+	}
+}
+
+func TestTableAgainstBig(t *testing.T) {
+	// Exhaustive comparison with exact values for small n
+	for _, pe := range []struct {
+		p uint64
+		e int
+	}{{2, 1}, {2, 5}, {3, 3}, {5, 1}, {7, 2}, {13, 1}} {
+		tb, err := NewTable(pe.p, pe.e)
+		if err != nil {
+			t.Fatalf("NewTable(%d, %d) returned error: %v", pe.p, pe.e, err)
+		}
+		m := new(big.Int).SetUint64(tb.Modulus())
+
+		fact := big.NewInt(1)
+		for n := uint64(0); n <= 120; n++ {
+			if n > 0 {
+				fact.Mul(fact, new(big.Int).SetUint64(n))
+			}
+			want := new(big.Int).Mod(fact, m).Uint64()
+			if got := tb.Factorial(n); got != want {
+				t.Errorf("Table(%d^%d).Factorial(%d) = %d, want %d", pe.p, pe.e, n, got, want)
+			}
+
+			for k := uint64(0); k <= n+1; k++ {
+				c := new(big.Int).Binomial(int64(n), int64(k))
+				if k > n {
+					c.SetInt64(0)
+				}
+				want := c.Mod(c, m).Uint64()
+				if got := tb.Binomial(n, k); got != want {
+					t.Fatalf("Table(%d^%d).Binomial(%d, %d) = %d, want %d", pe.p, pe.e, n, k, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestTableFactorialPrimeFree(t *testing.T) {
+	tb, err := NewTable(5, 1)
+	if err != nil {
+		t.Fatalf("NewTable(5, 1) returned error: %v", err)
+	}
+	// 30! = 2^26 3^14 5^7 7^4 11^2 13^2 17 19 23 29
+	f := new(big.Int).MulRange(1, 30)
+	f.Quo(f, new(big.Int).Exp(big.NewInt(5), big.NewInt(7), nil))
+	free, exp := tb.FactorialPrimeFree(30)
+	if exp != 7 || free != new(big.Int).Mod(f, big.NewInt(5)).Uint64() {
+		t.Errorf("FactorialPrimeFree(30) = %d, %d", free, exp)
+	}
+}
+
+func TestTableLargeN(t *testing.T) {
+	// Lucas and Granville must agree with each other for a prime modulus,
+	// and Lucas with CRT over a composite modulus
+	const p = 1_000_003
+	lucas, err := NewTable(p, 1)
+	if err != nil {
+		t.Fatalf("NewTable(%d, 1) returned error: %v", p, err)
+	}
+	n, k := uint64(1_000_000_000_000), uint64(123_456_789_012)
+	got := lucas.Binomial(n, k)
+
+	// Granville with e = 1, forced through the prime-power path
+	granville := &Table{p: lucas.p, pe: lucas.pe, e: 1, units: lucas.units}
+	num, c := granville.FactorialPrimeFree(n)
+	a, ca := granville.FactorialPrimeFree(k)
+	b, cb := granville.FactorialPrimeFree(n - k)
+	want := uint64(0)
+	if c-ca-cb == 0 {
+		inv, _ := InverseMod(MulMod(a, b, p), p)
+		want = MulMod(num, inv, p)
+	}
+	if got != want {
+		t.Errorf("Lucas C(%d, %d) mod %d = %d, Granville = %d", n, k, p, got, want)
+	}
+
+	// Wilson's theorem: (p-1)! = -1 and the p-free part of (p^2)! is 1
+	if f := lucas.Factorial(p - 1); f != p-1 {
+		t.Errorf("Factorial(p-1) = %d, want p-1", f)
+	}
+	if f, e := lucas.FactorialPrimeFree(p * p); f != 1 || e != p+1 {
+		t.Errorf("FactorialPrimeFree(p^2) = %d, %d, want 1, %d", f, e, p+1)
+	}
+}
@@ -0,0 +1,77 @@
+package combinatorics
+
+import (
+	"math/big"
+	"runtime"
+	"sync"
+)
+
+// minParallelChunk is the smallest range a worker is given; below it the
+// goroutine overhead outweighs the multiplication
+const minParallelChunk = 2048
+
+// ParallelFactorial calculates n! by splitting 1..n across workers
+// goroutines, each building a balanced product of its range, then
+// multiplying the partial products pairwise, also in parallel. workers <= 0
+// means runtime.GOMAXPROCS(0). With a single worker, or n too small to be
+// worth splitting, it is BigFactorial.
+func ParallelFactorial(n, workers int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 || n < 2*minParallelChunk {
+		return BigFactorial(n)
+	}
+	return parallelRangeProduct(1, uint64(n), workers), nil
+}
+
+// parallelRangeProduct returns lo * (lo+1) * ... * hi for lo <= hi
+func parallelRangeProduct(lo, hi uint64, workers int) *big.Int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	count := hi - lo + 1
+	if max := int(count / minParallelChunk); workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		return progressionProduct(lo, 1, int(count))
+	}
+
+	// Later numbers are larger, so equal counts give roughly equal work
+	parts := make([]*big.Int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		first := lo + count*uint64(w)/uint64(workers)
+		next := lo + count*uint64(w+1)/uint64(workers)
+		wg.Add(1)
+		go func(w int, first, next uint64) {
+			defer wg.Done()
+			parts[w] = progressionProduct(first, 1, int(next-first))
+		}(w, first, next)
+	}
+	wg.Wait()
+
+	return parallelProduct(parts)
+}
+
+// parallelProduct multiplies xs as a balanced tree, running the two halves
+// of each level concurrently
+func parallelProduct(xs []*big.Int) *big.Int {
+	if len(xs) == 1 {
+		return xs[0]
+	}
+	half := len(xs) / 2
+	var left *big.Int
+	done := make(chan struct{})
+	go func() {
+		left = parallelProduct(xs[:half])
+		close(done)
+	}()
+	right := parallelProduct(xs[half:])
+	<-done
+	return left.Mul(left, right)
+}
@@ -0,0 +1,62 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// sequentialFactorial is the plain loop the faster forms are measured against
+func sequentialFactorial(n int) *big.Int {
+	f := big.NewInt(1)
+	for i := 2; i <= n; i++ {
+		f.Mul(f, big.NewInt(int64(i)))
+	}
+	return f
+}
+
+func TestParallelFactorial(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 20, 2047, 2048, 4096, 10_001, 50_000} {
+		want, _ := BigFactorial(n)
+		for _, workers := range []int{0, 1, 2, 3, 7, 64} {
+			got, err := ParallelFactorial(n, workers)
+			if err != nil || got.Cmp(want) != 0 {
+				t.Fatalf("ParallelFactorial(%d, %d) differs from BigFactorial(%d), err = %v", n, workers, n, err)
+			}
+		}
+	}
+
+	if _, err := ParallelFactorial(-1, 2); !errors.Is(err, ErrNegative) {
+		t.Errorf("ParallelFactorial(-1, 2) error = %v, want ErrNegative", err)
+	}
+}
+
+func benchmarkFactorial(b *testing.B, n int, f func(int) *big.Int) {
+	for i := 0; i < b.N; i++ {
+		f(n)
+	}
+}
+
+func BenchmarkSequentialFactorial50000(b *testing.B) {
+	benchmarkFactorial(b, 50_000, sequentialFactorial)
+}
+
+func BenchmarkBigFactorial50000(b *testing.B) {
+	benchmarkFactorial(b, 50_000, func(n int) *big.Int { f, _ := BigFactorial(n); return f })
+}
+
+func BenchmarkParallelFactorial50000(b *testing.B) {
+	benchmarkFactorial(b, 50_000, func(n int) *big.Int { f, _ := ParallelFactorial(n, 0); return f })
+}
+
+func BenchmarkSequentialFactorial200000(b *testing.B) {
+	benchmarkFactorial(b, 200_000, sequentialFactorial)
+}
+
+func BenchmarkBigFactorial200000(b *testing.B) {
+	benchmarkFactorial(b, 200_000, func(n int) *big.Int { f, _ := BigFactorial(n); return f })
+}
+
+func BenchmarkParallelFactorial200000(b *testing.B) {
+	benchmarkFactorial(b, 200_000, func(n int) *big.Int { f, _ := ParallelFactorial(n, 0); return f })
+}
@@ -0,0 +1,73 @@
+package combinatorics
+
+import "math/big"
+
+// primesUpTo returns the primes <= n in increasing order using a sieve of
+// Eratosthenes over the odd numbers
+func primesUpTo(n int) []int {
+	if n < 2 {
+		return nil
+	}
+
+	// composite[i] reports whether 2i+1 is composite
+	composite := make([]bool, n/2+1)
+	for i := 1; (2*i+1)*(2*i+1) <= n; i++ {
+		if composite[i] {
+			continue
+		}
+		p := 2*i + 1
+		for j := p * p / 2; j < len(composite); j += p {
+			composite[j] = true
+		}
+	}
+
+	primes := []int{2}
+	for i := 1; 2*i+1 <= n; i++ {
+		if !composite[i] {
+			primes = append(primes, 2*i+1)
+		}
+	}
+	return primes
+}
+
+// legendre returns the exponent of the prime p in n!, the sum of n/p^i
+func legendre(n, p int) int {
+	e := 0
+	for n >= p {
+		n /= p
+		e += n
+	}
+	return e
+}
+
+// productOfPowers returns the product of primes[i]^exps[i]. Factors are
+// packed into machine words first and the words multiplied as a balanced
+// tree.
+func productOfPowers(primes []int, exps []int) *big.Int {
+	var words []uint64
+	acc := uint64(1)
+	for i, p := range primes {
+		for e := 0; e < exps[i]; e++ {
+			if acc > ^uint64(0)/uint64(p) {
+				words = append(words, acc)
+				acc = 1
+			}
+			acc *= uint64(p)
+		}
+	}
+	words = append(words, acc)
+	return productOfWords(words)
+}
+
+// productOfWords multiplies words by splitting them in halves
+func productOfWords(words []uint64) *big.Int {
+	switch len(words) {
+	case 0:
+		return big.NewInt(1)
+	case 1:
+		return new(big.Int).SetUint64(words[0])
+	}
+	half := len(words) / 2
+	left := productOfWords(words[:half])
+	return left.Mul(left, productOfWords(words[half:]))
+}
@@ -0,0 +1,214 @@
+package combinatorics
+
+import (
+	"math"
+	"math/big"
+)
+
+// The functions below describe n! without computing it. Small n are
+// answered exactly from BigFactorial; larger n use Legendre's formula or
+// the logarithm of n! at just enough precision to decide the answer.
+
+// maxExactProperties is the largest n for which DigitCount and
+// LeadingDigits format n! exactly
+const maxExactProperties = 1000
+
+// maxExtraPrecision bounds the extra bits DigitCount and LeadingDigits try
+// before they give up on the logarithm and format n! exactly
+const maxExtraPrecision = 1 << 12
+
+// PrimePower is one prime factor of a factorization
+type PrimePower struct {
+	Prime    int
+	Exponent int
+}
+
+// PrimeExponent returns the exponent of the prime p in n! by Legendre's
+// formula, n/p + n/p^2 + ..., in O(log_p n) steps. It returns ErrDomain if p
+// is not prime.
+func PrimeExponent(n, p int) (int, error) {
+	if err := checkNonNegative(n); err != nil {
+		return 0, err
+	}
+	if p < 2 || !big.NewInt(int64(p)).ProbablyPrime(0) {
+		return 0, ErrDomain
+	}
+	return legendre(n, p), nil
+}
+
+// FactorialFactorization returns the prime factorization of n! in
+// increasing order of primes
+func FactorialFactorization(n int) ([]PrimePower, error) {
+	if err := checkNonNegative(n); err != nil {
+		return nil, err
+	}
+	primes := primesUpTo(n)
+	factors := make([]PrimePower, len(primes))
+	for i, p := range primes {
+		factors[i] = PrimePower{Prime: p, Exponent: legendre(n, p)}
+	}
+	return factors, nil
+}
+
+// TrailingZeros returns the number of trailing zeros of n! written in base,
+// the largest k with base^k dividing n!. For each prime power q^a of the
+// base that is (exponent of q in n!) / a, and the scarcest prime decides.
+func TrailingZeros(n, base int) (int, error) {
+	if err := checkNonNegative(n); err != nil {
+		return 0, err
+	}
+	if base < 2 {
+		return 0, ErrDomain
+	}
+
+	zeros := math.MaxInt
+	for _, f := range factorInt(base) {
+		if z := legendre(n, f.Prime) / f.Exponent; z < zeros {
+			zeros = z
+		}
+	}
+	return zeros, nil
+}
+
+// factorInt factors m >= 2 by trial division
+func factorInt(m int) []PrimePower {
+	var factors []PrimePower
+	for p := 2; p*p <= m; p++ {
+		if m%p != 0 {
+			continue
+		}
+		f := PrimePower{Prime: p}
+		for m%p == 0 {
+			m /= p
+			f.Exponent++
+		}
+		factors = append(factors, f)
+	}
+	if m > 1 {
+		factors = append(factors, PrimePower{Prime: m, Exponent: 1})
+	}
+	return factors
+}
+
+// DigitCount returns the number of digits of n! in base 2 to 62, which is
+// floor(log_base n!) + 1
+func DigitCount(n, base int) (int, error) {
+	if err := checkDigitArgs(n, base); err != nil {
+		return 0, err
+	}
+	if n <= maxExactProperties {
+		return len(factorialText(n, base)), nil
+	}
+
+	// float64 decides unless log_base n! is within its error of an integer
+	lf, _ := LogFactorial(n)
+	l := lf / math.Log(float64(base))
+	tolerance := l*1e-13 + 1e-9
+	if _, frac := math.Modf(l); frac > tolerance && frac < 1-tolerance {
+		return int(l) + 1, nil
+	}
+
+	for extra := uint(64); extra <= maxExtraPrecision; extra *= 2 {
+		whole, frac := splitFloat(logBaseFactorial(n, base, extra))
+		if !nearInteger(frac, int(extra)-16) {
+			w, _ := whole.Int64()
+			return int(w) + 1, nil
+		}
+	}
+	return len(factorialText(n, base)), nil
+}
+
+// LeadingDigits returns the first count digits of n! in base 2 to 62, using
+// the digits 0-9, a-z, A-Z like big.Int.Text. It returns all digits if n!
+// has fewer than count.
+//
+// The prefix is exact once it reaches into the trailing zeros of n!, where
+// the logarithm can never tell it apart from an integer, so such counts
+// format n! in full.
+func LeadingDigits(n, count, base int) (string, error) {
+	if err := checkDigitArgs(n, base); err != nil {
+		return "", err
+	}
+	if count < 1 {
+		return "", ErrDomain
+	}
+	if n <= maxExactProperties {
+		return prefix(factorialText(n, base), count), nil
+	}
+	digits, _ := DigitCount(n, base)
+	zeros, _ := TrailingZeros(n, base)
+	if count >= digits-zeros {
+		return prefix(factorialText(n, base), count), nil
+	}
+
+	// With log_base n! = whole + frac, the leading digits are
+	// floor(base^(frac + count - 1))
+	lnBase := math.Log2(float64(base))
+	for extra := uint(64); extra <= maxExtraPrecision; extra *= 2 {
+		prec := uint(float64(count)*lnBase) + extra
+		_, frac := splitFloat(logBaseFactorial(n, base, prec))
+		e := frac.Add(frac, new(big.Float).SetInt64(int64(count-1)))
+		v := bigExp(e.Mul(e, bigLog(new(big.Float).SetInt64(int64(base)), prec)), prec)
+
+		// v is accurate to about 2^-extra; retry if that could move it
+		// across an integer
+		whole, vfrac := splitFloat(v)
+		if !nearInteger(vfrac, int(extra)-16) {
+			digits, _ := whole.Int(nil)
+			return digits.Text(base), nil
+		}
+	}
+	return prefix(factorialText(n, base), count), nil
+}
+
+// factorialText formats n! exactly in base
+func factorialText(n, base int) string {
+	f, _ := BigFactorial(n)
+	return f.Text(base)
+}
+
+// prefix returns the first count characters of s, or s if it is shorter
+func prefix(s string, count int) string {
+	if len(s) > count {
+		return s[:count]
+	}
+	return s
+}
+
+func checkDigitArgs(n, base int) error {
+	if err := checkNonNegative(n); err != nil {
+		return err
+	}
+	if base < 2 || base > 62 {
+		return ErrDomain
+	}
+	return nil
+}
+
+// logBaseFactorial returns log_base n! with extra bits of fractional
+// precision beyond the bits needed for its integer part
+func logBaseFactorial(n, base int, extra uint) *big.Float {
+	lf, _ := LogFactorial(n)
+	prec := uint(math.Log2(lf+2)) + extra
+	l, _ := BigLogFactorial(n, prec)
+	return l.Quo(l, bigLog(new(big.Float).SetInt64(int64(base)), prec))
+}
+
+// splitFloat returns the integer part of the non-negative x and the
+// remainder in [0, 1)
+func splitFloat(x *big.Float) (whole, frac *big.Float) {
+	i, _ := x.Int(nil)
+	whole = new(big.Float).SetPrec(x.Prec()).SetInt(i)
+	frac = new(big.Float).SetPrec(x.Prec()).Sub(x, whole)
+	return whole, frac
+}
+
+// nearInteger reports whether the fraction frac in [0, 1) is within 2^-bits
+// of 0 or 1
+func nearInteger(frac *big.Float, bits int) bool {
+	if frac.Sign() == 0 || frac.MantExp(nil) <= -bits {
+		return true
+	}
+	rest := new(big.Float).Sub(big.NewFloat(1), frac)
+	return rest.MantExp(nil) <= -bits
+}
@@ -0,0 +1,140 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPrimeExponent(t *testing.T) {
+	tests := []struct {
+		n, p, want int
+	}{
+		{100, 5, 24},
+		{100, 97, 1},
+		{1000, 2, 994},
+		{1_000_000_000_000, 5, 249_999_999_997},
+		{3, 5, 0},
+	}
+
+	for _, tt := range tests {
+		got, err := PrimeExponent(tt.n, tt.p)
+		if err != nil || got != tt.want {
+			t.Errorf("PrimeExponent(%d, %d) = %d, %v, want %d", tt.n, tt.p, got, err, tt.want)
+		}
+	}
+
+	if _, err := PrimeExponent(10, 4); !errors.Is(err, ErrDomain) {
+		t.Errorf("PrimeExponent(10, 4) error = %v, want ErrDomain", err)
+	}
+	if _, err := PrimeExponent(-1, 2); !errors.Is(err, ErrNegative) {
+		t.Errorf("PrimeExponent(-1, 2) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestFactorialFactorization(t *testing.T) {
+	got, err := FactorialFactorization(10)
+	if err != nil {
+		t.Fatalf("FactorialFactorization(10) returned error: %v", err)
+	}
+	want := []PrimePower{{2, 8}, {3, 4}, {5, 2}, {7, 1}}
+	if len(got) != len(want) {
+		t.Fatalf("FactorialFactorization(10) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("FactorialFactorization(10) = %v, want %v", got, want)
+		}
+	}
+
+	// The factorization multiplies back to n!
+	factors, _ := FactorialFactorization(500)
+	product := big.NewInt(1)
+	for _, f := range factors {
+		product.Mul(product, new(big.Int).Exp(big.NewInt(int64(f.Prime)), big.NewInt(int64(f.Exponent)), nil))
+	}
+	if f, _ := BigFactorial(500); product.Cmp(f) != 0 {
+		t.Error("FactorialFactorization(500) does not multiply back to 500!")
+	}
+}
+
+func TestTrailingZeros(t *testing.T) {
+	for n := 0; n <= 200; n += 7 {
+		f, _ := BigFactorial(n)
+		for base := 2; base <= 40; base++ {
+			s := f.Text(base)
+			want := len(s) - len(strings.TrimRight(s, "0"))
+			if got, err := TrailingZeros(n, base); err != nil || got != want {
+				t.Fatalf("TrailingZeros(%d, %d) = %d, %v, want %d", n, base, got, err, want)
+			}
+		}
+	}
+
+	if got, _ := TrailingZeros(1_000_000_000, 10); got != 249_999_998 {
+		t.Errorf("TrailingZeros(1e9, 10) = %d, want 249999998", got)
+	}
+	if _, err := TrailingZeros(10, 1); !errors.Is(err, ErrDomain) {
+		t.Errorf("TrailingZeros(10, 1) error = %v, want ErrDomain", err)
+	}
+}
+
+func TestDigitCount(t *testing.T) {
+	// Past maxExactProperties the logarithm must agree with the exact count
+	for n := maxExactProperties - 2; n <= maxExactProperties+60; n++ {
+		f, _ := BigFactorial(n)
+		for _, base := range []int{2, 10, 16, 62} {
+			if got, err := DigitCount(n, base); err != nil || got != len(f.Text(base)) {
+				t.Fatalf("DigitCount(%d, %d) = %d, %v, want %d", n, base, got, err, len(f.Text(base)))
+			}
+		}
+	}
+
+	tests := []struct {
+		n, base, want int
+	}{
+		{0, 10, 1},
+		{100_000, 10, 456_574},
+		{1_000_000, 10, 5_565_709},
+		{1_000_000, 2, 18_488_885},
+	}
+	for _, tt := range tests {
+		if got, err := DigitCount(tt.n, tt.base); err != nil || got != tt.want {
+			t.Errorf("DigitCount(%d, %d) = %d, %v, want %d", tt.n, tt.base, got, err, tt.want)
+		}
+	}
+
+	if _, err := DigitCount(10, 63); !errors.Is(err, ErrDomain) {
+		t.Errorf("DigitCount(10, 63) error = %v, want ErrDomain", err)
+	}
+}
+
+func TestLeadingDigits(t *testing.T) {
+	for n := maxExactProperties + 1; n <= maxExactProperties+40; n++ {
+		f, _ := BigFactorial(n)
+		for _, base := range []int{10, 16} {
+			want := f.Text(base)[:25]
+			if got, err := LeadingDigits(n, 25, base); err != nil || got != want {
+				t.Fatalf("LeadingDigits(%d, 25, %d) = %q, %v, want %q", n, base, got, err, want)
+			}
+		}
+	}
+
+	if got, _ := LeadingDigits(1_000_000, 11, 10); got != "82639316883" {
+		t.Errorf("LeadingDigits(1e6, 11, 10) = %q, want 82639316883", got)
+	}
+	// 1001! has 2571 digits of which the last 249 are zeros, so the first
+	// 2322 digits are an integer the logarithm cannot round to
+	f, _ := BigFactorial(1001)
+	for _, count := range []int{2322, 2400} {
+		if got, err := LeadingDigits(1001, count, 10); err != nil || got != f.Text(10)[:count] {
+			t.Errorf("LeadingDigits(1001, %d, 10) = %q, %v, want the exact prefix", count, got, err)
+		}
+	}
+	if got, _ := LeadingDigits(5, 10, 10); got != "120" {
+		t.Errorf("LeadingDigits(5, 10, 10) = %q, want 120", got)
+	}
+	if _, err := LeadingDigits(5, 0, 10); !errors.Is(err, ErrDomain) {
+		t.Errorf("LeadingDigits(5, 0, 10) error = %v, want ErrDomain", err)
+	}
+}
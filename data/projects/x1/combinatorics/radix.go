@@ -0,0 +1,127 @@
+package combinatorics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Progress is called while digits are written, with the number of digits
+// written so far and the total number of digits
+type Progress func(written, total int64)
+
+// radixLeafBits is roughly the size of the chunks converted with
+// big.Int.Text; everything above is split by division
+const radixLeafBits = 8192
+
+// WriteFactorial writes n! in base 2 to 62 to w, as WriteInt does. Progress
+// covers only the conversion, not computing n!.
+func WriteFactorial(w io.Writer, n, base int, progress Progress) (int64, error) {
+	if base < 2 || base > 62 {
+		return 0, ErrDomain
+	}
+	f, err := BigFactorial(n)
+	if err != nil {
+		return 0, err
+	}
+	return WriteInt(w, f, base, progress)
+}
+
+// WriteInt writes x in base 2 to 62 to w, using the digits 0-9, a-z, A-Z like
+// big.Int.Text, and returns the number of bytes written. Rather than
+// building the whole string, it divides x by base^(k*2^i) for a chunk size
+// k, recursively writing the quotient and then the remainder padded to
+// k*2^i digits, so digits reach w from the most significant end as they are
+// produced. Besides x, it holds only the powers base^(k*2^i), together
+// about the size of x. progress, if not nil, is called after each chunk.
+func WriteInt(w io.Writer, x *big.Int, base int, progress Progress) (int64, error) {
+	if base < 2 || base > 62 {
+		return 0, ErrDomain
+	}
+
+	c := &radixWriter{out: bufio.NewWriter(w), base: base, progress: progress}
+	if x.Sign() < 0 {
+		c.out.WriteByte('-')
+		c.bytes++
+		x = new(big.Int).Neg(x)
+	}
+
+	// k digits of base fit in about radixLeafBits bits
+	c.leafDigits = int(radixLeafBits / math.Log2(float64(base)))
+	c.powers = []*big.Int{new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(c.leafDigits)), nil)}
+	for c.powers[len(c.powers)-1].Cmp(x) <= 0 {
+		last := c.powers[len(c.powers)-1]
+		c.powers = append(c.powers, new(big.Int).Mul(last, last))
+	}
+
+	c.write(x, len(c.powers)-2, false)
+	if err := c.out.Flush(); err != nil && c.err == nil {
+		c.err = err
+	}
+	return c.bytes, c.err
+}
+
+// radixWriter holds the state of one WriteInt call
+type radixWriter struct {
+	out        *bufio.Writer
+	base       int
+	progress   Progress
+	leafDigits int
+	// powers[i] is base^(leafDigits * 2^i); the last one exceeds x
+	powers []*big.Int
+
+	// pending counts the padded digits still to come below the leading
+	// chunk, so the total is known once that chunk is converted
+	pending int64
+	written int64
+	total   int64
+	bytes   int64
+	err     error
+}
+
+// write writes x < powers[level+1]. Padded parts are written with exactly
+// leafDigits * 2^(level+1) digits; the leading part has no leading zeros.
+func (c *radixWriter) write(x *big.Int, level int, padded bool) {
+	if c.err != nil {
+		return
+	}
+	if level < 0 {
+		c.writeLeaf(x, padded)
+		return
+	}
+
+	power := c.powers[level]
+	if !padded && x.Cmp(power) < 0 {
+		c.write(x, level-1, false)
+		return
+	}
+
+	q, r := new(big.Int).QuoRem(x, power, new(big.Int))
+	if !padded {
+		c.pending += int64(c.leafDigits) << level
+	}
+	c.write(q, level-1, padded)
+	c.write(r, level-1, true)
+}
+
+func (c *radixWriter) writeLeaf(x *big.Int, padded bool) {
+	s := x.Text(c.base)
+	if padded {
+		s = strings.Repeat("0", c.leafDigits-len(s)) + s
+	} else {
+		c.total = int64(len(s)) + c.pending
+	}
+
+	n, err := c.out.WriteString(s)
+	c.bytes += int64(n)
+	c.written += int64(len(s))
+	if err != nil {
+		c.err = err
+		return
+	}
+	if c.progress != nil {
+		c.progress(c.written, c.total)
+	}
+}
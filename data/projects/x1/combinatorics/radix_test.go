@@ -0,0 +1,104 @@
+package combinatorics
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestWriteInt(t *testing.T) {
+	values := []*big.Int{big.NewInt(0), big.NewInt(7), big.NewInt(-12345)}
+	for _, n := range []int{100, 2000, 5000} {
+		f, _ := BigFactorial(n)
+		values = append(values, f, new(big.Int).Neg(f), new(big.Int).Add(f, big.NewInt(1)))
+	}
+	// Powers of the base and their neighbours have the most chunk borders
+	// with zeros
+	for _, e := range []int64{1000, 5000} {
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(e), nil)
+		values = append(values, p, new(big.Int).Sub(p, big.NewInt(1)))
+	}
+
+	for _, x := range values {
+		for _, base := range []int{2, 3, 10, 16, 36, 62} {
+			var buf bytes.Buffer
+			var calls int
+			var last, total int64
+			ordered := true
+			n, err := WriteInt(&buf, x, base, func(written, tot int64) {
+				if written <= last || (calls > 0 && tot != total) {
+					ordered = false
+				}
+				calls++
+				last, total = written, tot
+			})
+			want := x.Text(base)
+			if err != nil || buf.String() != want || n != int64(len(want)) {
+				t.Fatalf("WriteInt(%d bits, %d) wrote %d bytes, err %v, differs from Text", x.BitLen(), base, n, err)
+			}
+			digits := int64(len(want))
+			if x.Sign() < 0 {
+				digits--
+			}
+			if !ordered || last != digits || total != digits {
+				t.Fatalf("WriteInt(%d bits, %d) progress ended at %d of %d, ordered %v, want %d", x.BitLen(), base, last, total, ordered, digits)
+			}
+		}
+	}
+
+	if _, err := WriteInt(&bytes.Buffer{}, big.NewInt(1), 63, nil); !errors.Is(err, ErrDomain) {
+		t.Errorf("WriteInt base 63 error = %v, want ErrDomain", err)
+	}
+}
+
+// failingWriter accepts limit bytes and then fails
+type failingWriter struct {
+	limit int
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errWrite
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriteIntError(t *testing.T) {
+	f, _ := BigFactorial(20000)
+	if _, err := WriteInt(&failingWriter{limit: 10000}, f, 10, nil); !errors.Is(err, errWrite) {
+		t.Errorf("WriteInt to a failing writer error = %v, want %v", err, errWrite)
+	}
+}
+
+func TestWriteFactorial(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := WriteFactorial(&buf, 3000, 10, nil); err != nil {
+		t.Fatalf("WriteFactorial(3000) returned error: %v", err)
+	}
+	if f, _ := BigFactorial(3000); buf.String() != f.String() {
+		t.Error("WriteFactorial(3000) differs from BigFactorial(3000)")
+	}
+	if _, err := WriteFactorial(&buf, -1, 10, nil); !errors.Is(err, ErrNegative) {
+		t.Errorf("WriteFactorial(-1) error = %v, want ErrNegative", err)
+	}
+}
+
+func BenchmarkWriteFactorial100000(b *testing.B) {
+	f, _ := BigFactorial(100_000)
+	for i := 0; i < b.N; i++ {
+		WriteInt(&bytes.Buffer{}, f, 10, nil)
+	}
+}
+
+func BenchmarkText100000(b *testing.B) {
+	f, _ := BigFactorial(100_000)
+	for i := 0; i < b.N; i++ {
+		_ = f.Text(10)
+	}
+}
@@ -0,0 +1,85 @@
+package combinatorics
+
+import "math/big"
+
+// The Stirling numbers are computed over the band of the triangle that the
+// result depends on, cells s(j+d, j) for 0 <= j <= k and 0 <= d <= n-k. Every
+// cell feeds into s(n, k) with a positive integer coefficient, so no cell is
+// larger than the result and the int64 forms only fail when the result itself
+// does not fit. Each takes O(k(n-k)) steps and O(k) memory.
+
+// StirlingFirst returns the unsigned Stirling number of the first kind
+// c(n, k), the number of permutations of n items with exactly k cycles
+func StirlingFirst(n, k int) (int64, error) {
+	return stirling("StirlingFirst", n, k, func(j, d int) int64 { return int64(j + d - 1) })
+}
+
+// StirlingSecond returns the Stirling number of the second kind S(n, k), the
+// number of ways to partition n items into k non-empty sets
+func StirlingSecond(n, k int) (int64, error) {
+	return stirling("StirlingSecond", n, k, func(j, d int) int64 { return int64(j) })
+}
+
+// BigStirlingFirst returns c(n, k) exactly
+func BigStirlingFirst(n, k int) (*big.Int, error) {
+	return bigStirling(n, k, func(j, d int) int64 { return int64(j + d - 1) })
+}
+
+// BigStirlingSecond returns S(n, k) exactly
+func BigStirlingSecond(n, k int) (*big.Int, error) {
+	return bigStirling(n, k, func(j, d int) int64 { return int64(j) })
+}
+
+// stirling evaluates s(j+d, j) = coef(j, d) * s(j+d-1, j) + s(j+d-1, j-1)
+// with s(j, j) = 1 and s(d, 0) = 0 for d > 0
+func stirling(fn string, n, k int, coef func(j, d int) int64) (int64, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return 0, err
+	}
+	if k > n {
+		return 0, nil
+	}
+
+	row := make([]int64, k+1)
+	for j := range row {
+		row[j] = 1
+	}
+	for d := 1; d <= n-k; d++ {
+		row[0] = 0
+		for j := 1; j <= k; j++ {
+			v, ok := mulInt64(coef(j, d), row[j])
+			if ok {
+				v, ok = addInt64(v, row[j-1])
+			}
+			if !ok {
+				return 0, overflow(fn, n, k)
+			}
+			row[j] = v
+		}
+	}
+	return row[k], nil
+}
+
+// bigStirling is stirling with big.Int cells
+func bigStirling(n, k int, coef func(j, d int) int64) (*big.Int, error) {
+	if err := checkNonNegative(n, k); err != nil {
+		return nil, err
+	}
+	if k > n {
+		return new(big.Int), nil
+	}
+
+	row := make([]*big.Int, k+1)
+	for j := range row {
+		row[j] = big.NewInt(1)
+	}
+	c := new(big.Int)
+	for d := 1; d <= n-k; d++ {
+		row[0].SetInt64(0)
+		for j := 1; j <= k; j++ {
+			row[j].Mul(row[j], c.SetInt64(coef(j, d)))
+			row[j].Add(row[j], row[j-1])
+		}
+	}
+	return row[k], nil
+}
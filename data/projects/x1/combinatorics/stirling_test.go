@@ -0,0 +1,80 @@
+package combinatorics
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// stirlingTable builds rows 0..n of a Stirling triangle with the full
+// recurrence, as a reference for the banded computation
+func stirlingTable(n int, first bool) [][]*big.Int {
+	table := make([][]*big.Int, n+1)
+	for m := range table {
+		table[m] = make([]*big.Int, n+1)
+		for k := range table[m] {
+			table[m][k] = new(big.Int)
+		}
+	}
+	table[0][0].SetInt64(1)
+	for m := 1; m <= n; m++ {
+		for k := 1; k <= m; k++ {
+			coef := int64(k)
+			if first {
+				coef = int64(m - 1)
+			}
+			table[m][k].Mul(table[m-1][k], big.NewInt(coef))
+			table[m][k].Add(table[m][k], table[m-1][k-1])
+		}
+	}
+	return table
+}
+
+func TestStirlingNumbers(t *testing.T) {
+	const n = 40
+	for _, first := range []bool{true, false} {
+		table := stirlingTable(n, first)
+		name, small, exact := "StirlingSecond", StirlingSecond, BigStirlingSecond
+		if first {
+			name, small, exact = "StirlingFirst", StirlingFirst, BigStirlingFirst
+		}
+
+		for m := 0; m <= n; m++ {
+			for k := 0; k <= m+1; k++ {
+				want := new(big.Int)
+				if k <= n {
+					want = table[m][k]
+				}
+
+				got, err := exact(m, k)
+				if err != nil || got.Cmp(want) != 0 {
+					t.Fatalf("Big%s(%d, %d) = %v, %v, want %s", name, m, k, got, err, want)
+				}
+
+				v, err := small(m, k)
+				if want.IsInt64() {
+					if err != nil || v != want.Int64() {
+						t.Fatalf("%s(%d, %d) = %d, %v, want %s", name, m, k, v, err, want)
+					}
+				} else {
+					var overflow *OverflowError
+					if !errors.As(err, &overflow) {
+						t.Fatalf("%s(%d, %d) error = %v, want *OverflowError", name, m, k, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestStirlingKnownValues(t *testing.T) {
+	if got, _ := StirlingSecond(10, 3); got != 9330 {
+		t.Errorf("StirlingSecond(10, 3) = %d, want 9330", got)
+	}
+	if got, _ := StirlingFirst(10, 3); got != 1172700 {
+		t.Errorf("StirlingFirst(10, 3) = %d, want 1172700", got)
+	}
+	if _, err := StirlingFirst(-1, 0); !errors.Is(err, ErrNegative) {
+		t.Errorf("StirlingFirst(-1, 0) error = %v, want ErrNegative", err)
+	}
+}
@@ -0,0 +1,320 @@
+package combinatorics
+
+import (
+	"math"
+	"math/big"
+)
+
+// MultiFactorial returns the k-multifactorial n(n-k)(n-2k)... down to the
+// last positive factor. It is 1 for -k < n <= 0, so e.g. (-1)!! = 1, and
+// undefined for smaller n or k < 1.
+func MultiFactorial(n, k int) (int64, error) {
+	if k < 1 {
+		return 0, ErrDomain
+	}
+	if n <= -k {
+		return 0, ErrNegative
+	}
+
+	result := int64(1)
+	for i := n; i > 0; i -= k {
+		var ok bool
+		if result, ok = mulInt64(result, int64(i)); !ok {
+			return 0, overflow("MultiFactorial", n, k)
+		}
+	}
+	return result, nil
+}
+
+// BigMultiFactorial returns the k-multifactorial of n exactly
+func BigMultiFactorial(n, k int) (*big.Int, error) {
+	if k < 1 {
+		return nil, ErrDomain
+	}
+	if n <= -k {
+		return nil, ErrNegative
+	}
+	if n <= 0 {
+		return big.NewInt(1), nil
+	}
+	// Factors are n mod k (or k), ..., n in steps of k
+	count := (n-1)/k + 1
+	first := n - (count-1)*k
+	return progressionProduct(uint64(first), uint64(k), count), nil
+}
+
+// DoubleFactorial returns n!! = n(n-2)(n-4)..., with 0!! = (-1)!! = 1
+func DoubleFactorial(n int) (int64, error) {
+	v, err := MultiFactorial(n, 2)
+	if err != nil {
+		return 0, renameOverflow(err, "DoubleFactorial", n)
+	}
+	return v, nil
+}
+
+// BigDoubleFactorial returns n!! exactly
+func BigDoubleFactorial(n int) (*big.Int, error) {
+	return BigMultiFactorial(n, 2)
+}
+
+// Subfactorial returns !n, the number of derangements of n items
+// (permutations with no fixed point), from !n = n * !(n-1) + (-1)^n
+func Subfactorial(n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+
+	result := int64(1)
+	for i := 1; i <= n; i++ {
+		var ok bool
+		if result, ok = mulInt64(result, int64(i)); !ok {
+			return 0, overflow("Subfactorial", n)
+		}
+		// i * !(i-1) >= 1 for odd i >= 3, and !1 = 0
+		if i%2 == 1 {
+			result--
+		} else if result, ok = addInt64(result, 1); !ok {
+			return 0, overflow("Subfactorial", n)
+		}
+	}
+	return result, nil
+}
+
+// BigSubfactorial returns !n exactly. Each step of the recurrence is the
+// affine map d -> i*d + (-1)^i, and the maps are composed as a balanced
+// tree instead of one at a time.
+func BigSubfactorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n == 0 {
+		return big.NewInt(1), nil
+	}
+	a, b := derangementMaps(1, n)
+	// Apply the composed map to !0 = 1
+	return a.Add(a, b), nil
+}
+
+// derangementMaps returns (a, b) such that applying the steps lo..hi to d
+// gives a*d + b
+func derangementMaps(lo, hi int) (a, b *big.Int) {
+	if lo == hi {
+		sign := int64(1)
+		if lo%2 == 1 {
+			sign = -1
+		}
+		return big.NewInt(int64(lo)), big.NewInt(sign)
+	}
+	mid := lo + (hi-lo)/2
+	a1, b1 := derangementMaps(lo, mid)
+	a2, b2 := derangementMaps(mid+1, hi)
+	// a2*(a1*d + b1) + b2
+	b1.Mul(b1, a2)
+	b1.Add(b1, b2)
+	return a1.Mul(a1, a2), b1
+}
+
+// Superfactorial returns sf(n) = 1! * 2! * ... * n!
+func Superfactorial(n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+
+	result, fact := int64(1), int64(1)
+	for i := 1; i <= n; i++ {
+		var ok bool
+		if fact, ok = mulInt64(fact, int64(i)); ok {
+			result, ok = mulInt64(result, fact)
+		}
+		if !ok {
+			return 0, overflow("Superfactorial", n)
+		}
+	}
+	return result, nil
+}
+
+// BigSuperfactorial returns sf(n) exactly from its prime factorization: the
+// exponent of p is the sum of its exponents in 1!, 2!, ..., n!
+func BigSuperfactorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	return productOfPrimeExponents(n, func(p int) int64 {
+		// sum over k <= n of floor(k/q) for every power q of p
+		var e int64
+		for q := p; q <= n; {
+			t := int64(n / q)
+			e += int64(q)*t*(t-1)/2 + t*(int64(n)-t*int64(q)+1)
+			if q > n/p {
+				break
+			}
+			q *= p
+		}
+		return e
+	}), nil
+}
+
+// Hyperfactorial returns H(n) = 1^1 * 2^2 * ... * n^n
+func Hyperfactorial(n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+
+	result := int64(1)
+	for i := 2; i <= n; i++ {
+		for j := 0; j < i; j++ {
+			var ok bool
+			if result, ok = mulInt64(result, int64(i)); !ok {
+				return 0, overflow("Hyperfactorial", n)
+			}
+		}
+	}
+	return result, nil
+}
+
+// BigHyperfactorial returns H(n) exactly from its prime factorization: the
+// exponent of p is the sum of the multiples m of each power of p up to n
+func BigHyperfactorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	return productOfPrimeExponents(n, func(p int) int64 {
+		var e int64
+		for q := p; q <= n; {
+			t := int64(n / q)
+			e += int64(q) * t * (t + 1) / 2
+			if q > n/p {
+				break
+			}
+			q *= p
+		}
+		return e
+	}), nil
+}
+
+// productOfPrimeExponents returns the product of p^exponent(p) over the
+// primes p <= n, multiplying the powers as a balanced tree
+func productOfPrimeExponents(n int, exponent func(p int) int64) *big.Int {
+	primes := primesUpTo(n)
+	powers := make([]*big.Int, len(primes))
+	for i, p := range primes {
+		powers[i] = new(big.Int).Exp(big.NewInt(int64(p)), big.NewInt(exponent(p)), nil)
+	}
+	return productOfBigs(powers)
+}
+
+// productOfBigs multiplies xs by splitting them in halves
+func productOfBigs(xs []*big.Int) *big.Int {
+	switch len(xs) {
+	case 0:
+		return big.NewInt(1)
+	case 1:
+		return xs[0]
+	}
+	half := len(xs) / 2
+	left := productOfBigs(xs[:half])
+	return new(big.Int).Mul(left, productOfBigs(xs[half:]))
+}
+
+// RisingFactorial returns the Pochhammer symbol x(x+1)...(x+n-1), which is
+// 1 for n = 0. x may be negative.
+func RisingFactorial(x, n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+	if n > 0 && x > math.MaxInt-(n-1) {
+		return 0, overflow("RisingFactorial", x, n)
+	}
+	return rangeProduct("RisingFactorial", x, x+n-1, x, n)
+}
+
+// FallingFactorial returns x(x-1)...(x-n+1), which is 1 for n = 0 and
+// equals n! / (x-n)! for x >= n >= 0. x may be negative.
+func FallingFactorial(x, n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+	if n > 0 && x < math.MinInt+(n-1) {
+		return 0, overflow("FallingFactorial", x, n)
+	}
+	return rangeProduct("FallingFactorial", x-n+1, x, x, n)
+}
+
+// BigRisingFactorial returns x(x+1)...(x+n-1) exactly
+func BigRisingFactorial(x, n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n > 0 && x > math.MaxInt-(n-1) {
+		return nil, overflow("BigRisingFactorial", x, n)
+	}
+	return new(big.Int).MulRange(int64(x), int64(x+n-1)), nil
+}
+
+// BigFallingFactorial returns x(x-1)...(x-n+1) exactly
+func BigFallingFactorial(x, n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	if n > 0 && x < math.MinInt+(n-1) {
+		return nil, overflow("BigFallingFactorial", x, n)
+	}
+	return new(big.Int).MulRange(int64(x-n+1), int64(x)), nil
+}
+
+// rangeProduct returns lo * (lo+1) * ... * hi, or 1 for an empty range
+func rangeProduct(fn string, lo, hi, x, n int) (int64, error) {
+	if lo <= 0 && hi >= 0 {
+		return 0, nil
+	}
+	result := int64(1)
+	for i := lo; i <= hi; i++ {
+		var ok bool
+		if result, ok = mulSignedInt64(result, int64(i)); !ok {
+			return 0, overflow(fn, x, n)
+		}
+	}
+	return result, nil
+}
+
+// Primorial returns n#, the product of the primes <= n
+func Primorial(n int) (int64, error) {
+	if n < 0 {
+		return 0, ErrNegative
+	}
+
+	// 53# is the first primorial past int64, so a short sieve suffices
+	if n > 64 {
+		return 0, overflow("Primorial", n)
+	}
+	result := int64(1)
+	for _, p := range primesUpTo(n) {
+		var ok bool
+		if result, ok = mulInt64(result, int64(p)); !ok {
+			return 0, overflow("Primorial", n)
+		}
+	}
+	return result, nil
+}
+
+// BigPrimorial returns n# exactly
+func BigPrimorial(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	primes := primesUpTo(n)
+	ones := make([]int, len(primes))
+	for i := range ones {
+		ones[i] = 1
+	}
+	return productOfPowers(primes, ones), nil
+}
+
+// renameOverflow reports an overflow from a shared implementation under
+// the public function's name and arguments
+func renameOverflow(err error, fn string, args ...int) error {
+	if _, ok := err.(*OverflowError); ok {
+		return overflow(fn, args...)
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package combinatorics
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// checkVariant compares a fixed-size function with its exact form: they
+// must agree wherever the exact value fits in an int64, and the fixed-size
+// form must report an *OverflowError everywhere else
+func checkVariant(t *testing.T, name string, n int, small func() (int64, error), exact func() (*big.Int, error)) {
+	t.Helper()
+	want, err := exact()
+	if err != nil {
+		t.Fatalf("Big%s(%d) returned error: %v", name, n, err)
+	}
+	got, err := small()
+	if want.IsInt64() {
+		if err != nil || got != want.Int64() {
+			t.Errorf("%s(%d) = %d, %v, want %s", name, n, got, err, want)
+		}
+		return
+	}
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) || overflow.Func != name {
+		t.Errorf("%s(%d) error = %v, want *OverflowError from %s", name, n, err, name)
+	}
+}
+
+func TestMultiFactorial(t *testing.T) {
+	// n!!! for n = 0..12 (OEIS A007661)
+	triple := []int64{1, 1, 2, 3, 4, 10, 18, 28, 80, 162, 280, 880, 1944}
+	for n, want := range triple {
+		if got, err := MultiFactorial(n, 3); err != nil || got != want {
+			t.Errorf("MultiFactorial(%d, 3) = %d, %v, want %d", n, got, err, want)
+		}
+	}
+
+	for k := 1; k <= 4; k++ {
+		for n := -k + 1; n <= 80; n++ {
+			n, k := n, k
+			checkVariant(t, "MultiFactorial", n,
+				func() (int64, error) { return MultiFactorial(n, k) },
+				func() (*big.Int, error) { return BigMultiFactorial(n, k) })
+		}
+	}
+
+	// MultiFactorial(n, 1) is the factorial
+	f, _ := BigFactorial(300)
+	if got, _ := BigMultiFactorial(300, 1); got.Cmp(f) != 0 {
+		t.Errorf("BigMultiFactorial(300, 1) differs from BigFactorial(300)")
+	}
+
+	// n + k overflows at the int edge, so the factor count must not use it;
+	// the factors are MaxInt and, for k = MaxInt-1, also 1
+	for _, k := range []int{math.MaxInt, math.MaxInt - 1} {
+		if got, err := BigMultiFactorial(math.MaxInt, k); err != nil || got.Cmp(big.NewInt(math.MaxInt)) != 0 {
+			t.Errorf("BigMultiFactorial(MaxInt, %d) = %v, %v, want %d", k, got, err, math.MaxInt)
+		}
+	}
+
+	if _, err := MultiFactorial(5, 0); !errors.Is(err, ErrDomain) {
+		t.Errorf("MultiFactorial(5, 0) error = %v, want ErrDomain", err)
+	}
+	if _, err := MultiFactorial(-3, 3); !errors.Is(err, ErrNegative) {
+		t.Errorf("MultiFactorial(-3, 3) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestDoubleFactorial(t *testing.T) {
+	want := []int64{1, 1, 2, 3, 8, 15, 48, 105, 384, 945, 3840}
+	for n, w := range want {
+		if got, err := DoubleFactorial(n); err != nil || got != w {
+			t.Errorf("DoubleFactorial(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+	if got, err := DoubleFactorial(-1); err != nil || got != 1 {
+		t.Errorf("DoubleFactorial(-1) = %d, %v, want 1", got, err)
+	}
+	for n := 0; n <= 60; n++ {
+		n := n
+		checkVariant(t, "DoubleFactorial", n,
+			func() (int64, error) { return DoubleFactorial(n) },
+			func() (*big.Int, error) { return BigDoubleFactorial(n) })
+	}
+}
+
+func TestSubfactorial(t *testing.T) {
+	want := []int64{1, 0, 1, 2, 9, 44, 265, 1854, 14833, 133496, 1334961}
+	for n, w := range want {
+		if got, err := Subfactorial(n); err != nil || got != w {
+			t.Errorf("Subfactorial(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+
+	// Reference: the plain recurrence
+	d := big.NewInt(1)
+	for n := 0; n <= 500; n++ {
+		if n > 0 {
+			d.Mul(d, big.NewInt(int64(n)))
+			if n%2 == 1 {
+				d.Sub(d, big.NewInt(1))
+			} else {
+				d.Add(d, big.NewInt(1))
+			}
+		}
+		got, err := BigSubfactorial(n)
+		if err != nil || got.Cmp(d) != 0 {
+			t.Fatalf("BigSubfactorial(%d) = %v, %v, want %s", n, got, err, d)
+		}
+		if n <= 30 {
+			n := n
+			checkVariant(t, "Subfactorial", n,
+				func() (int64, error) { return Subfactorial(n) },
+				func() (*big.Int, error) { return BigSubfactorial(n) })
+		}
+	}
+}
+
+func TestSuperAndHyperfactorial(t *testing.T) {
+	super := []int64{1, 1, 2, 12, 288, 34560, 24883200, 125411328000}
+	hyper := []int64{1, 1, 4, 108, 27648, 86400000, 4031078400000}
+	for n, w := range super {
+		if got, err := Superfactorial(n); err != nil || got != w {
+			t.Errorf("Superfactorial(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+	for n, w := range hyper {
+		if got, err := Hyperfactorial(n); err != nil || got != w {
+			t.Errorf("Hyperfactorial(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+
+	// Reference: the defining products
+	sf, hf, fact := big.NewInt(1), big.NewInt(1), big.NewInt(1)
+	for n := 0; n <= 150; n++ {
+		if n > 0 {
+			bn := big.NewInt(int64(n))
+			fact.Mul(fact, bn)
+			sf.Mul(sf, fact)
+			hf.Mul(hf, new(big.Int).Exp(bn, bn, nil))
+		}
+		if got, _ := BigSuperfactorial(n); got.Cmp(sf) != 0 {
+			t.Fatalf("BigSuperfactorial(%d) = %s, want %s", n, got, sf)
+		}
+		if got, _ := BigHyperfactorial(n); got.Cmp(hf) != 0 {
+			t.Fatalf("BigHyperfactorial(%d) = %s, want %s", n, got, hf)
+		}
+		if n <= 12 {
+			n := n
+			checkVariant(t, "Superfactorial", n,
+				func() (int64, error) { return Superfactorial(n) },
+				func() (*big.Int, error) { return BigSuperfactorial(n) })
+			checkVariant(t, "Hyperfactorial", n,
+				func() (int64, error) { return Hyperfactorial(n) },
+				func() (*big.Int, error) { return BigHyperfactorial(n) })
+		}
+	}
+}
+
+func TestRisingFallingFactorial(t *testing.T) {
+	tests := []struct {
+		x, n            int
+		rising, falling int64
+	}{
+		{5, 0, 1, 1},
+		{5, 3, 210, 60},
+		{1, 5, 120, 0},
+		{-3, 2, 6, 12},
+		{-3, 3, -6, -60},
+		{-2, 4, 0, 120},
+	}
+
+	for _, tt := range tests {
+		if got, err := RisingFactorial(tt.x, tt.n); err != nil || got != tt.rising {
+			t.Errorf("RisingFactorial(%d, %d) = %d, %v, want %d", tt.x, tt.n, got, err, tt.rising)
+		}
+		if got, err := FallingFactorial(tt.x, tt.n); err != nil || got != tt.falling {
+			t.Errorf("FallingFactorial(%d, %d) = %d, %v, want %d", tt.x, tt.n, got, err, tt.falling)
+		}
+	}
+
+	for x := -30; x <= 30; x += 7 {
+		for n := 0; n <= 40; n++ {
+			x, n := x, n
+			checkVariant(t, "RisingFactorial", n,
+				func() (int64, error) { return RisingFactorial(x, n) },
+				func() (*big.Int, error) { return BigRisingFactorial(x, n) })
+			checkVariant(t, "FallingFactorial", n,
+				func() (int64, error) { return FallingFactorial(x, n) },
+				func() (*big.Int, error) { return BigFallingFactorial(x, n) })
+		}
+	}
+
+	if _, err := RisingFactorial(3, -1); !errors.Is(err, ErrNegative) {
+		t.Errorf("RisingFactorial(3, -1) error = %v, want ErrNegative", err)
+	}
+}
+
+func TestPrimorial(t *testing.T) {
+	want := map[int]int64{0: 1, 1: 1, 2: 2, 3: 6, 4: 6, 5: 30, 10: 210, 30: 6469693230}
+	for n, w := range want {
+		if got, err := Primorial(n); err != nil || got != w {
+			t.Errorf("Primorial(%d) = %d, %v, want %d", n, got, err, w)
+		}
+	}
+
+	p := big.NewInt(1)
+	for n := 0; n <= 200; n++ {
+		if n >= 2 && new(big.Int).SetInt64(int64(n)).ProbablyPrime(20) {
+			p.Mul(p, big.NewInt(int64(n)))
+		}
+		if got, _ := BigPrimorial(n); got.Cmp(p) != 0 {
+			t.Fatalf("BigPrimorial(%d) = %s, want %s", n, got, p)
+		}
+		n := n
+		checkVariant(t, "Primorial", n,
+			func() (int64, error) { return Primorial(n) },
+			func() (*big.Int, error) { return BigPrimorial(n) })
+	}
+}
@@ -1,56 +1,293 @@
+// Command factorial prints n! for each number or range given on the command
+// line, or read whitespace-separated from standard input when there are no
+// arguments (or an argument is "-").
+//
+//	factorial 20
+//	factorial --big 1..100
+//	seq 1 50 | factorial --digits --csv
+//	factorial --mod 1000000007 1000000000000
+//
+// Without --big the result must fit in an int, as with
+// combinatorics.Factorial. With --big, plain output is streamed as it is
+// converted, so even the millions of digits of 1000000! never exist as one
+// string; --progress reports how far that conversion has got. With --mod,
+// n! mod a prime m is 0 for n >= m and otherwise needs n or m-1-n to be at
+// most modular.MaxProductTerms (2^24); see modular.FactorialMod. One line (or
+// CSV row, or JSON object) is written per number; errors go to standard error
+// and processing continues with the next input. The exit status is 0 on
+// success, 1 if some factorial could not be computed, and 2 for invalid flags
+// or malformed input.
 package main
 
 import (
+	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/example/factorial/combinatorics"
+	"github.com/example/factorial/combinatorics/modular"
+)
+
+// Exit statuses; when several inputs fail the largest one is returned
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
 )
 
-// Factorial calculates the factorial of a non-negative integer n.
-// Returns an error if n is negative.
-// Note: For large values of n, this may overflow int.
-func Factorial(n int) (int, error) {
-	if n < 0 {
-		return 0, errors.New("factorial is not defined for negative numbers")
+const usage = `usage: factorial [flags] [N | A..B | -]...
+
+Prints N! for each number N and each N in the inclusive range A..B. With no
+arguments, or the argument "-", numbers and ranges are read from standard
+input.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options holds the parsed command-line flags
+type options struct {
+	big      bool
+	mod      uint64
+	hasMod   bool
+	base     int
+	digits   bool
+	format   string
+	progress bool
+}
+
+// run is the whole command, taking its arguments and streams as parameters
+// so tests can drive it
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, inputs, err := parseArgs(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
 	}
-	
-	// Base case: 0! = 1
-	if n == 0 {
-		return 1, nil
+	if err != nil {
+		fmt.Fprintf(stderr, "factorial: %v\n", err)
+		return exitUsage
 	}
-	
-	// Calculate factorial iteratively
-	result := 1
-	for i := 1; i <= n; i++ {
-		result *= i
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	c := newCalculator(opts, newWriter(opts, out, stderr))
+
+	status := exitOK
+	for _, input := range inputs {
+		if input != "-" {
+			status = max(status, c.process(input, stderr))
+			continue
+		}
+		scanner := bufio.NewScanner(stdin)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			status = max(status, c.process(scanner.Text(), stderr))
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(stderr, "factorial: reading input: %v\n", err)
+			status = max(status, exitFailure)
+		}
 	}
-	
-	return result, nil
+
+	if err := c.w.flush(); err != nil {
+		fmt.Fprintf(stderr, "factorial: %v\n", err)
+		return max(status, exitFailure)
+	}
+	return status
 }
 
-func main() {
-	// Test cases: 0, 1, 5, 10
-	testCases := []int{0, 1, 5, 10}
-	
-	fmt.Println("=== Factorial Function Demonstration ===")
-	fmt.Println()
-	
-	for _, n := range testCases {
-		result, err := Factorial(n)
+// parseArgs parses flags and returns the remaining inputs. Flags may follow
+// inputs, as in "factorial 1..10 --big".
+func parseArgs(args []string, stderr io.Writer) (*options, []string, error) {
+	opts := &options{}
+	var jsonOut, csvOut bool
+
+	fs := flag.NewFlagSet("factorial", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&opts.big, "big", false, "compute exact factorials of any size")
+	fs.Uint64Var(&opts.mod, "mod", 0, "print n! modulo `m` instead; n! mod a prime needs n or m-1-n at most 2^24 unless n >= m")
+	fs.IntVar(&opts.base, "base", 10, "print results in `base` 2 to 62")
+	fs.BoolVar(&opts.digits, "digits", false, "print the number of digits of n! instead")
+	fs.BoolVar(&jsonOut, "json", false, "print one JSON object per line")
+	fs.BoolVar(&csvOut, "csv", false, "print CSV with a header row")
+	fs.BoolVar(&opts.progress, "progress", false, "report progress writing large factorials on standard error")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	var inputs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		inputs = append(inputs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "mod" {
+			opts.hasMod = true
+		}
+	})
+
+	switch {
+	case opts.base < 2 || opts.base > 62:
+		return nil, nil, fmt.Errorf("--base must be between 2 and 62, got %d", opts.base)
+	case opts.hasMod && opts.mod == 0:
+		return nil, nil, errors.New("--mod must be positive")
+	case opts.hasMod && opts.digits:
+		return nil, nil, errors.New("--mod and --digits cannot be combined")
+	case jsonOut && csvOut:
+		return nil, nil, errors.New("--json and --csv cannot be combined")
+	case jsonOut:
+		opts.format = "json"
+	case csvOut:
+		opts.format = "csv"
+	default:
+		opts.format = "plain"
+	}
+	return opts, inputs, nil
+}
+
+// calculator computes the requested value for each n. Consecutive n reuse
+// the previous factorial, so a range costs one multiplication per number.
+type calculator struct {
+	opts *options
+	w    writer
+
+	// prevN and prev hold the last exact factorial (with --big) or residue
+	// (with --mod), for extending it to prevN+1
+	prevN    uint64
+	prev     *big.Int
+	prevMod  uint64
+	havePrev bool
+}
+
+func newCalculator(opts *options, w writer) *calculator {
+	return &calculator{opts: opts, w: w}
+}
+
+// process handles one number or range, returning its exit status
+func (c *calculator) process(input string, stderr io.Writer) int {
+	first, last, err := parseInput(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "factorial: %v\n", err)
+		return exitUsage
+	}
+
+	status := exitOK
+	for n := first; ; n++ {
+		value, err := c.value(n)
+		if err == nil {
+			err = c.w.write(n, value)
+		}
 		if err != nil {
-			fmt.Printf("Factorial(%d) -> Error: %v\n", n, err)
-		} else {
-			fmt.Printf("Factorial(%d) = %d\n", n, result)
+			fmt.Fprintf(stderr, "factorial: %d: %v\n", n, err)
+			status = exitFailure
+		}
+		if n == last {
+			return status
 		}
 	}
-	
-	// Demonstrate error handling with negative input
-	fmt.Println()
-	fmt.Println("=== Error Handling Demo ===")
-	negativeInput := -5
-	result, err := Factorial(negativeInput)
+}
+
+// parseInput parses "N" or "A..B" with A <= B
+func parseInput(input string) (first, last uint64, err error) {
+	lo, hi, isRange := strings.Cut(input, "..")
+	if first, err = parseNumber(lo); err != nil {
+		return 0, 0, fmt.Errorf("invalid input %q: %w", input, err)
+	}
+	if !isRange {
+		return first, first, nil
+	}
+	if last, err = parseNumber(hi); err != nil {
+		return 0, 0, fmt.Errorf("invalid input %q: %w", input, err)
+	}
+	if first > last {
+		return 0, 0, fmt.Errorf("invalid range %q: start is greater than end", input)
+	}
+	return first, last, nil
+}
+
+func parseNumber(s string) (uint64, error) {
+	if strings.HasPrefix(s, "-") {
+		return 0, combinatorics.ErrNegative
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
-		fmt.Printf("Factorial(%d) -> Error: %v\n", negativeInput, err)
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return 0, err
+	}
+	return n, nil
+}
+
+// value returns what to print for n, or an error if it cannot be computed
+func (c *calculator) value(n uint64) (any, error) {
+	if c.opts.hasMod {
+		return c.factorialMod(n)
+	}
+	if n > math.MaxInt {
+		return nil, fmt.Errorf("%d! is too large to compute; use --mod", n)
+	}
+
+	switch {
+	case c.opts.digits:
+		return combinatorics.DigitCount(int(n), c.opts.base)
+	case c.opts.big:
+		return bigNumber{c.bigFactorial(n), c.opts.base}, nil
+	}
+	f, err := combinatorics.Factorial(int(n))
+	var overflow *combinatorics.OverflowError
+	if errors.As(err, &overflow) {
+		return nil, fmt.Errorf("%w; use --big", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(int64(f)).Text(c.opts.base), nil
+}
+
+func (c *calculator) bigFactorial(n uint64) *big.Int {
+	if c.havePrev && c.prev != nil && n > 0 && n == c.prevN+1 {
+		c.prev.Mul(c.prev, new(big.Int).SetUint64(n))
+	} else {
+		// Each run is one-shot, so fresh values are computed directly
+		// rather than through a combinatorics.Cache
+		c.prev, _ = combinatorics.ParallelFactorial(int(n), 0)
+	}
+	c.prevN, c.havePrev = n, true
+	return c.prev
+}
+
+func (c *calculator) factorialMod(n uint64) (string, error) {
+	if c.havePrev && n > 0 && n == c.prevN+1 {
+		c.prevMod = modular.MulMod(c.prevMod, n%c.opts.mod, c.opts.mod)
 	} else {
-		fmt.Printf("Factorial(%d) = %d\n", negativeInput, result)
+		f, err := modular.FactorialMod(n, c.opts.mod)
+		if err != nil {
+			return "", fmt.Errorf("%d! mod %d: %w", n, c.opts.mod, err)
+		}
+		c.prevMod = f
 	}
+	c.prevN, c.havePrev = n, true
+	return new(big.Int).SetUint64(c.prevMod).Text(c.opts.base), nil
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/example/factorial/combinatorics"
+)
+
+// runCommand runs the command with args and stdin, returning its output and
+// exit status
+func runCommand(stdin string, args ...string) (stdout, stderr string, status int) {
+	var out, errOut bytes.Buffer
+	status = run(args, strings.NewReader(stdin), &out, &errOut)
+	return out.String(), errOut.String(), status
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"single", "", []string{"5"}, "120\n"},
+		{"range", "", []string{"0..4"}, "1\n1\n2\n6\n24\n"},
+		{"flags after inputs", "", []string{"25", "--big"}, "15511210043330985984000000\n"},
+		{"base", "", []string{"--base", "16", "10"}, "375f00\n"},
+		{"base 62", "", []string{"--big", "--base=62", "30"}, "1rK4HCZOAKdurI2wl9u\n"},
+		{"digits", "", []string{"--digits", "100", "1000000"}, "158\n5565709\n"},
+		{"mod", "", []string{"--mod", "1000000007", "1000000006", "5..7"}, "1000000006\n120\n720\n5040\n"},
+		{"stdin", "3\n4..5 6\n", nil, "6\n24\n120\n720\n"},
+		{"stdin dash", "3", []string{"2", "-", "4"}, "2\n6\n24\n"},
+		{"csv", "", []string{"--csv", "--digits", "9..10"}, "n,digits\n9,6\n10,7\n"},
+		{"json", "", []string{"--json", "4", "21", "--big"}, `{"n":4,"factorial":"24"}` + "\n" + `{"n":21,"factorial":"51090942171709440000"}` + "\n"},
+		{"json digits", "", []string{"--json", "--digits", "10"}, `{"n":10,"digits":7}` + "\n"},
+		{"json mod", "", []string{"--json", "--mod", "7", "3"}, `{"n":3,"factorial_mod":"6"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, status := runCommand(tt.stdin, tt.args...)
+			if status != exitOK || stdout != tt.want {
+				t.Errorf("factorial %v = %q, status %d, stderr %q, want %q", tt.args, stdout, status, stderr, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBigRange(t *testing.T) {
+	// Consecutive values extend the previous factorial; jumps use the cache
+	stdout, _, status := runCommand("", "--big", "95..105", "3", "200..201")
+	lines := strings.Fields(stdout)
+	ns := []int{95, 96, 97, 98, 99, 100, 101, 102, 103, 104, 105, 3, 200, 201}
+	if status != exitOK || len(lines) != len(ns) {
+		t.Fatalf("factorial --big printed %d lines, status %d, want %d lines", len(lines), status, len(ns))
+	}
+	for i, n := range ns {
+		want, _ := combinatorics.BigFactorial(n)
+		if lines[i] != want.String() {
+			t.Errorf("line %d = %s, want %d! = %s", i, lines[i], n, want)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOut    string
+		wantStatus int
+		wantErr    string
+	}{
+		{"overflow", []string{"20", "21", "3"}, "2432902008176640000\n6\n", exitFailure, "use --big"},
+		{"malformed", []string{"3", "x", "4"}, "6\n24\n", exitUsage, `invalid input "x"`},
+		{"usage beats failure", []string{"21", "x"}, "", exitUsage, "overflows"},
+		{"negative", []string{"5..-1"}, "", exitUsage, "negative"},
+		{"reversed range", []string{"5..3"}, "", exitUsage, "start is greater than end"},
+		{"bad base", []string{"--base", "63", "5"}, "", exitUsage, "--base"},
+		{"zero modulus", []string{"--mod", "0", "5"}, "", exitUsage, "--mod must be positive"},
+		{"conflicting formats", []string{"--json", "--csv", "5"}, "", exitUsage, "cannot be combined"},
+		{"unknown flag", []string{"--fast", "5"}, "", exitUsage, "flag provided but not defined"},
+		{"too large", []string{"--big", "18446744073709551615"}, "", exitFailure, "use --mod"},
+		{"mod too large", []string{"--mod", "1000000007", "400000000"}, "", exitFailure, "too many terms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, status := runCommand("", tt.args...)
+			if status != tt.wantStatus || stdout != tt.wantOut || !strings.Contains(stderr, tt.wantErr) {
+				t.Errorf("factorial %v = %q, status %d, stderr %q; want %q, status %d, stderr containing %q",
+					tt.args, stdout, status, stderr, tt.wantOut, tt.wantStatus, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	_, stderr, status := runCommand("", "--help")
+	if status != exitOK || !strings.Contains(stderr, "usage: factorial") || !strings.Contains(stderr, "-digits") {
+		t.Errorf("factorial --help = status %d, stderr %q", status, stderr)
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	stdout, stderr, status := runCommand("", "--big", "--progress", "5000")
+	want, _ := combinatorics.BigFactorial(5000)
+	if status != exitOK || stdout != want.String()+"\n" {
+		t.Fatalf("factorial --big --progress 5000 = status %d, output differs from 5000!", status)
+	}
+	if !strings.HasPrefix(stderr, "\r5000!: ") || !strings.HasSuffix(stderr, "\r5000!: 100%\n") {
+		t.Errorf("factorial --big --progress 5000 stderr = %q", stderr)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/example/factorial
+
+go 1.22
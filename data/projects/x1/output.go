@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/example/factorial/combinatorics"
+)
+
+// writer formats one result per n
+type writer interface {
+	write(n uint64, value any) error
+	flush() error
+}
+
+// newWriter returns the writer for opts.format. The column or field holding
+// the value is named after what is printed: factorial, factorial_mod or
+// digits.
+func newWriter(opts *options, out *bufio.Writer, stderr io.Writer) writer {
+	column := "factorial"
+	switch {
+	case opts.hasMod:
+		column = "factorial_mod"
+	case opts.digits:
+		column = "digits"
+	}
+
+	switch opts.format {
+	case "json":
+		return &jsonWriter{out: out, enc: json.NewEncoder(out), column: column}
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(out), column: column}
+	default:
+		w := &plainWriter{out: out}
+		if opts.progress {
+			w.stderr = stderr
+		}
+		return w
+	}
+}
+
+// bigNumber is an exact factorial to print in base. It is kept as a big.Int
+// so plainWriter can stream it; other writers format it with String.
+type bigNumber struct {
+	x    *big.Int
+	base int
+}
+
+func (b bigNumber) String() string {
+	return b.x.Text(b.base)
+}
+
+func (b bigNumber) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// plainWriter prints just the value, one per line. A bigNumber is written
+// with combinatorics.WriteInt, reporting progress to stderr if it is set.
+type plainWriter struct {
+	out    *bufio.Writer
+	stderr io.Writer
+}
+
+func (w *plainWriter) write(n uint64, value any) error {
+	b, ok := value.(bigNumber)
+	if !ok {
+		_, err := fmt.Fprintln(w.out, value)
+		return err
+	}
+
+	var progress combinatorics.Progress
+	if w.stderr != nil {
+		percent := -1
+		progress = func(written, total int64) {
+			if p := int(written * 100 / total); p != percent {
+				percent = p
+				fmt.Fprintf(w.stderr, "\r%d!: %d%%", n, p)
+			}
+		}
+	}
+	_, err := combinatorics.WriteInt(w.out, b.x, b.base, progress)
+	if w.stderr != nil {
+		fmt.Fprintln(w.stderr)
+	}
+	if err != nil {
+		return err
+	}
+	return w.out.WriteByte('\n')
+}
+
+func (w *plainWriter) flush() error {
+	return w.out.Flush()
+}
+
+// csvWriter prints a header row followed by n and the value
+type csvWriter struct {
+	w          *csv.Writer
+	column     string
+	wroteTitle bool
+}
+
+func (w *csvWriter) write(n uint64, value any) error {
+	if !w.wroteTitle {
+		w.wroteTitle = true
+		if err := w.w.Write([]string{"n", w.column}); err != nil {
+			return err
+		}
+	}
+	return w.w.Write([]string{strconv.FormatUint(n, 10), fmt.Sprint(value)})
+}
+
+func (w *csvWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// jsonWriter prints one object per line, e.g. {"n":5,"factorial":"120"}.
+// Factorials are strings so no digits are lost to float64 parsers; digit
+// counts are numbers.
+type jsonWriter struct {
+	out    *bufio.Writer
+	enc    *json.Encoder
+	column string
+}
+
+func (w *jsonWriter) write(n uint64, value any) error {
+	// A map would sort "n" after the value, so build the object in order
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	key, _ := json.Marshal(w.column)
+	return w.enc.Encode(json.RawMessage(fmt.Sprintf(`{"n":%d,%s:%s}`, n, key, encoded)))
+}
+
+func (w *jsonWriter) flush() error {
+	return w.out.Flush()
+}